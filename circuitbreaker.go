@@ -0,0 +1,123 @@
+package errands
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned for requests that weren't sent because the circuit breaker is open.
+var ErrCircuitOpen = errors.New("errands server circuit breaker is open")
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// circuitBreaker stops sending requests to the errands server after failureThreshold consecutive failures.
+// Once openTimeout has passed it lets a single probe request through, closing again if it succeeds.
+type circuitBreaker struct {
+	mu               sync.Mutex
+	failureThreshold int
+	openTimeout      time.Duration
+
+	state    circuitState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// EnableCircuitBreaker makes the API fail fast with ErrCircuitOpen after failureThreshold consecutive
+// requests fail with a network error or a 502, 503 or 504 status. While the breaker is open processors
+// stop claiming errands. Every openTimeout a single request is let through to probe whether the server is back.
+func (e *ErrandsAPI) EnableCircuitBreaker(failureThreshold int, openTimeout time.Duration) {
+	e.breaker = &circuitBreaker{
+		failureThreshold: failureThreshold,
+		openTimeout:      openTimeout,
+	}
+}
+
+// CircuitOpen reports whether requests are currently being held back by the circuit breaker.
+// It's false while the breaker is ready to let a probe request through.
+func (e *ErrandsAPI) CircuitOpen() bool {
+	if e.breaker == nil {
+		return false
+	}
+
+	e.breaker.mu.Lock()
+	defer e.breaker.mu.Unlock()
+
+	switch e.breaker.state {
+	case circuitOpen:
+		return time.Since(e.breaker.openedAt) < e.breaker.openTimeout
+	case circuitHalfOpen:
+		return e.breaker.probing
+	default:
+		return false
+	}
+}
+
+// allow returns ErrCircuitOpen if a request shouldn't be sent right now.
+func (b *circuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case circuitOpen:
+		if time.Since(b.openedAt) < b.openTimeout {
+			return ErrCircuitOpen
+		}
+		b.state = circuitHalfOpen
+		b.probing = true
+		return nil
+	case circuitHalfOpen:
+		if b.probing {
+			return ErrCircuitOpen
+		}
+		b.probing = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// record updates the breaker with the outcome of a request that allow let through.
+func (b *circuitBreaker) record(resp *http.Response, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	failed := err != nil
+	if resp != nil {
+		switch resp.StatusCode {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			failed = true
+		}
+	}
+
+	if !failed {
+		b.state = circuitClosed
+		b.failures = 0
+		b.probing = false
+		return
+	}
+
+	b.failures++
+	if b.state == circuitHalfOpen || b.failures >= b.failureThreshold {
+		b.state = circuitOpen
+		b.openedAt = time.Now()
+		b.probing = false
+	}
+}
+
+// abandon is called instead of record when a request that allow let through was never sent,
+// so another request can probe the server.
+func (b *circuitBreaker) abandon() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
@@ -0,0 +1,54 @@
+package errands
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var requests, healthy int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if atomic.LoadInt32(&healthy) == 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		w.Write([]byte(`{"status":"OK"}`))
+	}))
+	defer server.Close()
+
+	api := New(server.URL)
+	api.EnableCircuitBreaker(2, 50*time.Millisecond)
+
+	for i := 0; i < 2; i++ {
+		if _, err := api.GetErrands(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if !api.CircuitOpen() {
+		t.Fatal("expected circuit to be open after 2 failures")
+	}
+	if _, err := api.GetErrands(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 2 {
+		t.Fatalf("expected no request to be sent while open, got %d requests", n)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if api.CircuitOpen() {
+		t.Fatal("expected circuit to allow a probe after the open timeout")
+	}
+
+	atomic.StoreInt32(&healthy, 1)
+	if _, err := api.GetErrands(); err != nil {
+		t.Fatal(err)
+	}
+	if api.CircuitOpen() {
+		t.Error("expected circuit to close after a successful probe")
+	}
+}
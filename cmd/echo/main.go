@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -22,6 +23,7 @@ const (
 type Config struct {
 	ErrandsURL   string `envconfig:"ERRANDS_URL" required:"true"`
 	ErrandsTopic string `split_words:"true" default:"echo"`
+	HealthAddr   string `split_words:"true" default:":8080"`
 }
 
 func run() error {
@@ -36,6 +38,15 @@ func run() error {
 		return fmt.Errorf("new errand processor: %w", err)
 	}
 
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		if err := errandsClient.ServeHealth(ctx, cfg.HealthAddr, errands.DefaultUnreachableAfter); err != nil {
+			log.WithError(err).Error("health server stopped")
+		}
+	}()
+
 	sigs := make(chan os.Signal)
 	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
 
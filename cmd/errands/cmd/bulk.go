@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/polygon-io/errands-server/schemas"
+)
+
+// confirmSampleSize is the number of errands shown when asking to confirm a bulk action.
+const confirmSampleSize = 5
+
+// stdinIsTerminal reports whether stdin is a terminal that can answer a confirmation prompt. Tests replace it.
+var stdinIsTerminal = func() bool { return isTerminal(os.Stdin) }
+
+// bulkResult summarizes an action taken on many errands.
+type bulkResult struct {
+	succeeded int
+
+	// failed maps the IDs of errands the action failed for to the reason why.
+	failed map[string]error
+}
+
+// forEachErrand calls fn for each errand with at most concurrency calls running at once.
+// Errands that weren't attempted because ctx was canceled are counted as failures.
+func forEachErrand(ctx context.Context, jobs []schemas.Errand, concurrency int, fn func(ctx context.Context, job schemas.Errand) error) *bulkResult {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	result := &bulkResult{failed: make(map[string]error)}
+
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, concurrency)
+	)
+
+	for _, job := range jobs {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+
+		if err := ctx.Err(); err != nil {
+			mu.Lock()
+			result.failed[job.ID] = err
+			mu.Unlock()
+			continue
+		}
+
+		wg.Add(1)
+		go func(job schemas.Errand) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			err := fn(ctx, job)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				result.failed[job.ID] = err
+				return
+			}
+			result.succeeded++
+		}(job)
+	}
+
+	wg.Wait()
+
+	return result
+}
+
+// confirmBulk shows how many errands an action is about to be taken on, with a sample of them, and asks the user
+// to confirm. verb is the action, like delete, and done is its past tense, like deleted.
+func confirmBulk(jobs []schemas.Errand, verb, done string) (bool, error) {
+	if !stdinIsTerminal() {
+		return false, fmt.Errorf("refusing to %s %d errands without confirmation; pass --yes to %s them anyway", verb, len(jobs), verb)
+	}
+
+	sample := jobs
+	if len(sample) > confirmSampleSize {
+		sample = sample[:confirmSampleSize]
+	}
+
+	fmt.Fprintf(os.Stderr, "%d errands will be %s, including:\n\n", len(jobs), done)
+	if err := (&printer{format: "table"}).printList(os.Stderr, errandItems(sample), errandColumns); err != nil {
+		return false, err
+	}
+	if len(jobs) > len(sample) {
+		fmt.Fprintf(os.Stderr, "... and %d more\n", len(jobs)-len(sample))
+	}
+
+	fmt.Fprintf(os.Stderr, "\n%s %d errands? [y/N] ", verb, len(jobs))
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, fmt.Errorf("read confirmation: %w", err)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/polygon-io/errands-server/schemas"
+)
+
+func TestForEachErrand(t *testing.T) {
+	jobs := []schemas.Errand{{ID: "a"}, {ID: "b"}, {ID: "c"}, {ID: "d"}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	boom := errors.New("boom")
+	result := forEachErrand(ctx, jobs, 1, func(ctx context.Context, job schemas.Errand) error {
+		switch job.ID {
+		case "b":
+			return boom
+		case "c":
+			cancel()
+		}
+		return nil
+	})
+
+	if result.succeeded != 2 {
+		t.Errorf("expected 2 errands to succeed, got %d", result.succeeded)
+	}
+	if !errors.Is(result.failed["b"], boom) {
+		t.Errorf("expected b to fail with its error, got %v", result.failed["b"])
+	}
+	// d wasn't attempted, since ctx was canceled first.
+	if !errors.Is(result.failed["d"], context.Canceled) || len(result.failed) != 2 {
+		t.Errorf("expected d to fail because ctx was canceled, got %v", result.failed)
+	}
+}
@@ -0,0 +1,255 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// redacted replaces header values in config view, since they're usually credentials.
+const redacted = "REDACTED"
+
+// profileSettings are the settings config view --current shows.
+var profileSettings = []string{"endpoint", "bootstrap", "port", "kubeconfig", "kube-context", "namespace", "service", "output"}
+
+func (ec *errandsCmd) newConfigCommand() (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "shows and changes the errands config file",
+		Long: `
+		The config file is config.yaml (or .json or .toml) in the current directory or $HOME/.errands.
+		Its top-level keys set defaults for flags of the same name, like namespace or output, and headers
+		sets headers sent with every request.
+
+		Named profiles under profiles override those for a particular errands server:
+
+		  profile: staging
+		  profiles:
+		    staging:
+		      kube-context: staging
+		      namespace: polygon-staging
+		    prod:
+		      endpoint: https://errands.example.com
+		      output: wide
+		      headers:
+		        Authorization: Bearer <token>
+
+		The profile key picks the profile to use, and --profile or POLY_ERRANDS_PROFILE override it.
+		Profiles that set an endpoint don't port-forward unless they also set bootstrap.
+		`,
+		// Config commands don't talk to the errands server, so they skip setting up the API and port-forward.
+		PersistentPreRunE: ec.configPreRun,
+	}
+
+	view := &cobra.Command{
+		Use:   "view",
+		Short: "prints the config file, with header values redacted",
+		Args:  cobra.NoArgs,
+		RunE:  ec.configView,
+	}
+	view.Flags().Bool("current", false, "only print the settings in effect for the current profile, including flags and environment variables")
+	view.Flags().Bool("show-secrets", false, "print header values instead of redacting them")
+
+	useProfile := &cobra.Command{
+		Use:   "use-profile <profile>",
+		Short: "sets the profile used when --profile and POLY_ERRANDS_PROFILE aren't set",
+		Long: `
+		Sets the profile key of the config file. The file is rewritten by viper, so comments and
+		formatting aren't kept.
+		`,
+		Args: cobra.ExactArgs(1),
+		// The current profile isn't applied, so that use-profile can fix a config file that names one that doesn't exist.
+		PersistentPreRunE: ec.configUseProfilePreRun,
+		RunE:              ec.configUseProfile,
+	}
+
+	cmd.AddCommand(view)
+	cmd.AddCommand(useProfile)
+
+	return cmd, nil
+}
+
+func (ec *errandsCmd) configPreRun(cmd *cobra.Command, _ []string) error {
+	if err := ec.viper.BindPFlags(cmd.Flags()); err != nil {
+		return fmt.Errorf("bind pflags: %w", err)
+	}
+
+	return ec.loadConfig()
+}
+
+func (ec *errandsCmd) configUseProfilePreRun(cmd *cobra.Command, _ []string) error {
+	if err := ec.viper.BindPFlags(cmd.Flags()); err != nil {
+		return fmt.Errorf("bind pflags: %w", err)
+	}
+
+	return ec.readConfig()
+}
+
+// loadConfig reads the config file, if there is one, and layers the selected profile's settings over its top-level
+// ones. Flags and environment variables still take precedence over both.
+func (ec *errandsCmd) loadConfig() error {
+	if ec.configLoaded {
+		return nil
+	}
+
+	if err := ec.readConfig(); err != nil {
+		return err
+	}
+	ec.configLoaded = true
+
+	profile := ec.viper.GetString("profile")
+	if profile == "" {
+		return nil
+	}
+
+	if !ec.viper.IsSet("profiles." + profile) {
+		if ec.viper.ConfigFileUsed() == "" {
+			return fmt.Errorf("profile %q not found: no config file in . or $HOME/.errands", profile)
+		}
+		return fmt.Errorf("profile %q not found in %s", profile, ec.viper.ConfigFileUsed())
+	}
+
+	settings := ec.viper.GetStringMap("profiles." + profile)
+	if _, hasEndpoint := settings["endpoint"]; hasEndpoint {
+		if _, hasBootstrap := settings["bootstrap"]; !hasBootstrap {
+			settings["bootstrap"] = false
+		}
+	}
+
+	if err := ec.viper.MergeConfigMap(settings); err != nil {
+		return fmt.Errorf("apply profile %q: %w", profile, err)
+	}
+
+	return nil
+}
+
+// readConfig reads the config file if there is one.
+func (ec *errandsCmd) readConfig() error {
+	if err := ec.viper.ReadInConfig(); err != nil {
+		var notFound viper.ConfigFileNotFoundError
+		if !errors.As(err, &notFound) {
+			return fmt.Errorf("read config: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (ec *errandsCmd) configView(cmd *cobra.Command, args []string) error {
+	showSecrets := ec.viper.GetBool("show-secrets")
+
+	if ec.viper.GetBool("current") {
+		settings := map[string]interface{}{"profile": ec.viper.GetString("profile")}
+		for _, key := range profileSettings {
+			settings[key] = ec.viper.Get(key)
+		}
+		settings["headers"] = redactHeaders(ec.viper.GetStringMap("headers"), showSecrets)
+
+		return writeConfigYAML(settings)
+	}
+
+	path := ec.viper.ConfigFileUsed()
+	if path == "" {
+		return errors.New("no config file in . or $HOME/.errands")
+	}
+
+	file, err := readConfigFile(path)
+	if err != nil {
+		return err
+	}
+
+	settings := file.AllSettings()
+	if headers, ok := settings["headers"].(map[string]interface{}); ok {
+		settings["headers"] = redactHeaders(headers, showSecrets)
+	}
+	if profiles, ok := settings["profiles"].(map[string]interface{}); ok {
+		for _, p := range profiles {
+			profile, ok := p.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			if headers, ok := profile["headers"].(map[string]interface{}); ok {
+				profile["headers"] = redactHeaders(headers, showSecrets)
+			}
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "# %s\n", path)
+	return writeConfigYAML(settings)
+}
+
+func (ec *errandsCmd) configUseProfile(cmd *cobra.Command, args []string) error {
+	path := ec.viper.ConfigFileUsed()
+	if path == "" {
+		return errors.New("no config file in . or $HOME/.errands; create config.yaml with a profiles section first")
+	}
+
+	file, err := readConfigFile(path)
+	if err != nil {
+		return err
+	}
+
+	profile := args[0]
+	if !file.IsSet("profiles." + profile) {
+		return fmt.Errorf("profile %q not found in %s; expected one of %s", profile, path, strings.Join(profileNames(file), ", "))
+	}
+
+	file.Set("profile", profile)
+	if err := file.WriteConfig(); err != nil {
+		return fmt.Errorf("write config: %w", err)
+	}
+
+	fmt.Printf("using profile %s\n", profile)
+	return nil
+}
+
+// readConfigFile reads just the config file, without the flags, environment variables and profile layered on top.
+func readConfigFile(path string) (*viper.Viper, error) {
+	file := viper.New()
+	file.SetConfigFile(path)
+	if err := file.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+
+	return file, nil
+}
+
+func profileNames(file *viper.Viper) []string {
+	var names []string
+	for name := range file.GetStringMap("profiles") {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func redactHeaders(headers map[string]interface{}, showSecrets bool) map[string]interface{} {
+	if showSecrets {
+		return headers
+	}
+
+	redactedHeaders := make(map[string]interface{}, len(headers))
+	for key := range headers {
+		redactedHeaders[key] = redacted
+	}
+
+	return redactedHeaders
+}
+
+func writeConfigYAML(settings map[string]interface{}) error {
+	encoder := yaml.NewEncoder(os.Stdout)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(settings); err != nil {
+		return fmt.Errorf("encode yaml: %w", err)
+	}
+
+	return encoder.Close()
+}
@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const testConfig = `
+namespace: polygon-top
+output: wide
+headers:
+  X-Team: data
+profile: staging
+profiles:
+  staging:
+    kube-context: staging
+    namespace: polygon-staging
+  prod:
+    endpoint: https://errands.example.com
+    headers:
+      Authorization: Bearer secret
+  local:
+    endpoint: http://localhost:5555
+    bootstrap: true
+`
+
+// runErrands runs the errands CLI with args in a directory holding config, and returns what it wrote to stdout.
+func runErrands(t *testing.T, config string, args ...string) (string, error) {
+	t.Helper()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(dir)
+	t.Setenv("HOME", t.TempDir())
+
+	root, err := NewCommand()
+	if err != nil {
+		t.Fatal(err)
+	}
+	root.SetArgs(args)
+	root.SilenceErrors = true
+	root.SilenceUsage = true
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	output := make(chan string)
+	go func() {
+		b, _ := io.ReadAll(r)
+		output <- string(b)
+	}()
+
+	err = root.Execute()
+	w.Close()
+
+	return <-output, err
+}
+
+func TestConfigProfiles(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		env  map[string]string
+		want map[string]interface{}
+	}{
+		{
+			name: "profile from the config file",
+			want: map[string]interface{}{
+				"profile": "staging", "namespace": "polygon-staging", "kube-context": "staging", "output": "wide",
+				"bootstrap": true, "endpoint": "", "headers": map[string]interface{}{"x-team": redacted},
+			},
+		},
+		{
+			name: "profile flag with an endpoint",
+			args: []string{"--profile=prod"},
+			want: map[string]interface{}{
+				"profile": "prod", "namespace": "polygon-top", "kube-context": "", "output": "wide",
+				"bootstrap": false, "endpoint": "https://errands.example.com",
+				"headers": map[string]interface{}{"x-team": redacted, "authorization": redacted},
+			},
+		},
+		{
+			name: "show secrets",
+			args: []string{"--profile=prod", "--show-secrets"},
+			want: map[string]interface{}{
+				"headers": map[string]interface{}{"x-team": "data", "authorization": "Bearer secret"},
+			},
+		},
+		{
+			name: "profile with an endpoint that bootstraps",
+			args: []string{"--profile=local"},
+			want: map[string]interface{}{"bootstrap": true, "endpoint": "http://localhost:5555"},
+		},
+		{
+			name: "flags beat profiles",
+			args: []string{"--profile=prod", "--namespace=flag", "--bootstrap=true", "-o", "json"},
+			want: map[string]interface{}{"namespace": "flag", "bootstrap": true, "output": "json"},
+		},
+		{
+			name: "environment variables beat profiles",
+			env:  map[string]string{"POLY_ERRANDS_PROFILE": "prod", "POLY_ERRANDS_NAMESPACE": "env"},
+			want: map[string]interface{}{"profile": "prod", "namespace": "env", "endpoint": "https://errands.example.com"},
+		},
+		{
+			name: "flags beat environment variables",
+			args: []string{"--profile=staging"},
+			env:  map[string]string{"POLY_ERRANDS_PROFILE": "prod"},
+			want: map[string]interface{}{"profile": "staging", "namespace": "polygon-staging"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for key, value := range test.env {
+				t.Setenv(key, value)
+			}
+
+			output, err := runErrands(t, testConfig, append([]string{"config", "view", "--current"}, test.args...)...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var settings map[string]interface{}
+			if err := yaml.Unmarshal([]byte(output), &settings); err != nil {
+				t.Fatalf("decode output: %v\n%s", err, output)
+			}
+
+			for key, want := range test.want {
+				if got := settings[key]; !reflect.DeepEqual(got, want) {
+					t.Errorf("expected %s to be %v, got %v", key, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestConfigProfileErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		args   []string
+		err    string
+	}{
+		{
+			name:   "unknown profile flag",
+			config: testConfig,
+			args:   []string{"config", "view", "--current", "--profile=dev"},
+			err:    `profile "dev" not found in`,
+		},
+		{
+			name:   "unknown profile in the config file",
+			config: "profile: dev\n",
+			args:   []string{"config", "view", "--current"},
+			err:    `profile "dev" not found in`,
+		},
+		{
+			name:   "use unknown profile",
+			config: testConfig,
+			args:   []string{"config", "use-profile", "dev"},
+			err:    "expected one of local, prod, staging",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := runErrands(t, test.config, test.args...)
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("expected error containing %q, got %v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestConfigUseProfile(t *testing.T) {
+	// The config file names a profile that doesn't exist, which use-profile has to be able to fix.
+	config := strings.Replace(testConfig, "profile: staging", "profile: dev", 1)
+
+	output, err := runErrands(t, config, "config", "use-profile", "prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output != "using profile prod\n" {
+		t.Errorf("unexpected output: %q", output)
+	}
+
+	// runErrands left the working directory in the temporary directory with the rewritten config file.
+	b, err := os.ReadFile("config.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var file map[string]interface{}
+	if err := yaml.Unmarshal(b, &file); err != nil {
+		t.Fatal(err)
+	}
+	if file["profile"] != "prod" {
+		t.Errorf("expected the config file to use profile prod, got %v", file["profile"])
+	}
+	if _, ok := file["profiles"].(map[string]interface{})["staging"]; !ok {
+		t.Errorf("expected the other profiles to be kept, got %v", file["profiles"])
+	}
+}
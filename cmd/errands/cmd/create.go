@@ -0,0 +1,267 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	errandz "github.com/polygon-io/errands-go"
+	"github.com/polygon-io/errands-server/schemas"
+	"github.com/spf13/cobra"
+)
+
+// maxJSONLLineSize is the longest line allowed in a --from-file JSONL file.
+const maxJSONLLineSize = 10 * 1024 * 1024
+
+func (ec *errandsCmd) newCreateCommand() (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "creates an errand, or many errands from a JSONL file",
+		Long: `
+		Creates an errand of --type named --name. Its data can be given as --data key=value pairs
+		(values are strings), a JSON object in --data-file (use - to read it from stdin), or both,
+		with --data taking precedence.
+
+		With --from-file, an errand is created for each line of a JSONL file, where each line is an
+		errand object like {"name": "...", "type": "...", "data": {...}, "options": {...}}. The other
+		flags provide defaults for fields the lines leave out or leave empty. Options a line sets are
+		kept even if they're 0 or false, so {"options": {"retries": 0}} overrides --retries.
+		`,
+		PreRunE: ec.bindViperFlagsPreRun,
+		RunE:    ec.create,
+	}
+
+	cmd.Flags().String("type", "", "errand type")
+	cmd.Flags().String("name", "", "errand name; defaults to the type")
+	cmd.Flags().StringArray("data", nil, "set a data field as key=value; can be repeated")
+	cmd.Flags().String("data-file", "", "path to a JSON file with the errand's data, or - for stdin")
+	cmd.Flags().Int("priority", 0, "errand priority")
+	cmd.Flags().Int("ttl", 0, "errand TTL in seconds")
+	cmd.Flags().Int("retries", 0, "number of times to retry the errand if it fails")
+	cmd.Flags().Bool("delete-on-complete", false, "delete the errand once it completes")
+	cmd.Flags().String("from-file", "", "path to a JSONL file of errands to create, or - for stdin")
+
+	return cmd, nil
+}
+
+func (ec *errandsCmd) create(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fromFile := ec.viper.GetString("from-file")
+	if fromFile == "-" && ec.viper.GetString("data-file") == "-" {
+		return errors.New("--data-file and --from-file can't both read from stdin")
+	}
+
+	template, err := ec.errandFromFlags()
+	if err != nil {
+		return err
+	}
+
+	if fromFile == "" && template.Type == "" {
+		return errors.New("--type is required")
+	}
+
+	stopPortForward, err := ec.startPortForward(ctx)
+	if err != nil {
+		return err
+	}
+	defer stopPortForward()
+
+	if fromFile != "" {
+		return createErrandsFromFile(ctx, ec.api, fromFile, template)
+	}
+
+	id, err := createErrand(ctx, ec.api, template)
+	if err != nil {
+		return fmt.Errorf("create errand: %w", err)
+	}
+
+	fmt.Println(id)
+
+	return nil
+}
+
+// errandFromFlags builds an errand from the create command's flags.
+func (ec *errandsCmd) errandFromFlags() (*schemas.Errand, error) {
+	errand := &schemas.Errand{
+		Type: ec.viper.GetString("type"),
+		Name: ec.viper.GetString("name"),
+	}
+	errand.Options.Priority = ec.viper.GetInt("priority")
+	errand.Options.TTL = ec.viper.GetInt("ttl")
+	errand.Options.Retries = ec.viper.GetInt("retries")
+	errand.Options.DeleteOnCompleted = ec.viper.GetBool("delete-on-complete")
+
+	if dataFile := ec.viper.GetString("data-file"); dataFile != "" {
+		data, err := readFileOrStdin(dataFile)
+		if err != nil {
+			return nil, fmt.Errorf("read data file: %w", err)
+		}
+
+		if err := json.Unmarshal(data, &errand.Data); err != nil {
+			return nil, fmt.Errorf("parse data file %s: %w", dataFile, err)
+		}
+	}
+
+	for _, d := range ec.viper.GetStringSlice("data") {
+		key, value, ok := strings.Cut(d, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid --data %q; expected key=value", d)
+		}
+
+		if errand.Data == nil {
+			errand.Data = make(map[string]interface{})
+		}
+		errand.Data[key] = value
+	}
+
+	return errand, nil
+}
+
+// createErrandsFromFile creates an errand for each line of a JSONL file, filling in fields the lines leave out
+// from defaults. It carries on past errands that fail, and returns an error if any did.
+func createErrandsFromFile(ctx context.Context, api *errandz.ErrandsAPI, path string, defaults *schemas.Errand) error {
+	r := io.Reader(os.Stdin)
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("open errands file: %w", err)
+		}
+		defer f.Close()
+
+		r = f
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxJSONLLineSize)
+
+	var created, failed int
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+
+		errand := &schemas.Errand{}
+		if err := errand.UnmarshalJSON(scanner.Bytes()); err != nil {
+			fmt.Fprintf(os.Stderr, "line %d: invalid errand: %s\n", line, err)
+			failed++
+			continue
+		}
+
+		var set errandOptionsSet
+		if err := json.Unmarshal(scanner.Bytes(), &set); err != nil {
+			fmt.Fprintf(os.Stderr, "line %d: invalid errand: %s\n", line, err)
+			failed++
+			continue
+		}
+		applyErrandDefaults(errand, &set, defaults)
+
+		id, err := createErrand(ctx, api, errand)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "line %d: failed to create errand: %s\n", line, err)
+			failed++
+			continue
+		}
+
+		fmt.Println(id)
+		created++
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read errands file: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "created %d errands, %d failed\n", created, failed)
+	if failed > 0 {
+		return fmt.Errorf("failed to create %d errands", failed)
+	}
+
+	return nil
+}
+
+// errandOptionsSet records which options a --from-file line sets. schemas.Errand can't tell an option that's
+// explicitly 0 or false apart from one that's left out, and only the ones left out should get the flag defaults.
+type errandOptionsSet struct {
+	Options struct {
+		TTL               *int  `json:"ttl"`
+		Retries           *int  `json:"retries"`
+		Priority          *int  `json:"priority"`
+		DeleteOnCompleted *bool `json:"deleteOnCompleted"`
+	} `json:"options"`
+}
+
+// applyErrandDefaults fills in the fields of an errand from a --from-file line that the line leaves out.
+// An empty type or name counts as left out, since errands need both; options count as left out only if set
+// doesn't have them.
+func applyErrandDefaults(errand *schemas.Errand, set *errandOptionsSet, defaults *schemas.Errand) {
+	if errand.Type == "" {
+		errand.Type = defaults.Type
+	}
+
+	if errand.Name == "" {
+		errand.Name = defaults.Name
+	}
+
+	if set.Options.Priority == nil {
+		errand.Options.Priority = defaults.Options.Priority
+	}
+
+	if set.Options.TTL == nil {
+		errand.Options.TTL = defaults.Options.TTL
+	}
+
+	if set.Options.Retries == nil {
+		errand.Options.Retries = defaults.Options.Retries
+	}
+
+	if set.Options.DeleteOnCompleted == nil {
+		errand.Options.DeleteOnCompleted = defaults.Options.DeleteOnCompleted
+	}
+
+	for key, value := range defaults.Data {
+		if errand.Data == nil {
+			errand.Data = make(map[string]interface{})
+		}
+
+		if _, exists := errand.Data[key]; !exists {
+			errand.Data[key] = value
+		}
+	}
+}
+
+// createErrand creates an errand, naming it after its type if it has no name, and returns its ID.
+func createErrand(ctx context.Context, api *errandz.ErrandsAPI, errand *schemas.Errand) (string, error) {
+	if errand.Type == "" {
+		return "", errors.New("errand has no type")
+	}
+
+	if errand.Name == "" {
+		errand.Name = errand.Type
+	}
+
+	res, err := api.CreateErrandContext(ctx, errand)
+	if err != nil {
+		return "", err
+	}
+
+	if res.Results.ID == "" {
+		return "", fmt.Errorf("response status %q", res.Status)
+	}
+
+	return res.Results.ID, nil
+}
+
+func readFileOrStdin(path string) ([]byte, error) {
+	if path == "-" {
+		return ioutil.ReadAll(os.Stdin)
+	}
+
+	return ioutil.ReadFile(path)
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	errandz "github.com/polygon-io/errands-go"
+	"github.com/polygon-io/errands-server/schemas"
+)
+
+func TestCreateFlagErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		err  string
+	}{
+		{
+			name: "data file and errands file both from stdin",
+			args: []string{"--type=resize", "--data-file=-", "--from-file=-"},
+			err:  "--data-file and --from-file can't both read from stdin",
+		},
+		{
+			name: "no type",
+			args: []string{"--data", "width=100"},
+			err:  "--type is required",
+		},
+		{
+			name: "data without a value",
+			args: []string{"--type=resize", "--data", "width"},
+			err:  `invalid --data "width"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := runErrands(t, "", append([]string{"create", "--bootstrap=false"}, test.args...)...)
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("expected error containing %q, got %v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestCreateErrandsFromFileDefaults(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		created []schemas.Errand
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var errand schemas.Errand
+		if err := json.NewDecoder(r.Body).Decode(&errand); err != nil {
+			t.Error(err)
+		}
+
+		mu.Lock()
+		created = append(created, errand)
+		mu.Unlock()
+
+		w.Write([]byte(`{"status":"OK","results":{"id":"e1"}}`))
+	}))
+	defer server.Close()
+
+	lines := []string{
+		`{"data": {"width": 100}}`,
+		`{"name": "explicit", "type": "crop", "options": {"priority": 0, "ttl": 0, "retries": 0, "deleteOnCompleted": false}}`,
+		`{"options": {"retries": 1, "ttl": null}, "data": {"height": 10}}`,
+	}
+	path := filepath.Join(t.TempDir(), "errands.jsonl")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	defaults := &schemas.Errand{Type: "resize", Name: "resize-batch", Data: map[string]interface{}{"height": "50"}}
+	defaults.Options.Priority = 5
+	defaults.Options.TTL = 600
+	defaults.Options.Retries = 3
+	defaults.Options.DeleteOnCompleted = true
+
+	if err := createErrandsFromFile(context.Background(), errandz.New(server.URL), path, defaults); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []schemas.Errand{
+		{Name: "resize-batch", Type: "resize", Data: map[string]interface{}{"width": 100.0, "height": "50"}},
+		// Options the line sets to 0 or false override the defaults.
+		{Name: "explicit", Type: "crop", Data: map[string]interface{}{"height": "50"}},
+		{Name: "resize-batch", Type: "resize", Data: map[string]interface{}{"height": 10.0}},
+	}
+	expected[0].Options.Priority, expected[0].Options.TTL, expected[0].Options.Retries = 5, 600, 3
+	expected[0].Options.DeleteOnCompleted = true
+	expected[2].Options.Priority, expected[2].Options.TTL, expected[2].Options.Retries = 5, 600, 1
+	expected[2].Options.DeleteOnCompleted = true
+
+	if !reflect.DeepEqual(created, expected) {
+		t.Errorf("expected errands:\n%+v\ngot:\n%+v", expected, created)
+	}
+}
+
+func TestCreateFromFileKeepsErrorsOffStdout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var errand schemas.Errand
+		json.NewDecoder(r.Body).Decode(&errand)
+		if errand.Name == "rejected" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"message":"Errand validation failed!"}`))
+			return
+		}
+		w.Write([]byte(`{"status":"OK","results":{"id":"e1"}}`))
+	}))
+	defer server.Close()
+
+	// The path is absolute, since runErrands changes to a new working directory.
+	path := filepath.Join(t.TempDir(), "errands.jsonl")
+	lines := []string{`{"name": "ok"}`, `not json`, `{"name": "rejected"}`}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the IDs of the created errands go to stdout, so it can be piped into other commands.
+	output, err := runErrands(t, "", "create", "--bootstrap=false", "--endpoint="+server.URL, "--type=resize", "--from-file="+path)
+	if err == nil || !strings.Contains(err.Error(), "failed to create 2 errands") {
+		t.Errorf("expected 2 errands to fail, got %v", err)
+	}
+	if output != "e1\n" {
+		t.Errorf("expected only the created errand's ID on stdout, got %q", output)
+	}
+}
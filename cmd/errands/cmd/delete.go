@@ -2,25 +2,34 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 
 	errandz "github.com/polygon-io/errands-go"
+	"github.com/polygon-io/errands-server/schemas"
 	"github.com/spf13/cobra"
 )
 
 func (ec *errandsCmd) newDeleteCommand() (*cobra.Command, error) {
 	cmd := &cobra.Command{
-		Use:     "delete",
-		Short:   "deletes errands by ID, type, or status",
+		Use:   "delete",
+		Short: "deletes errands by ID, type, or status",
+		Long: `
+		Deletes an errand by --id, or every errand of a --type matching the filter flags.
+
+		Bulk deletes show how many errands match and a sample of them, then ask for confirmation
+		before deleting anything. Pass --yes to skip the prompt, which is required when stdin isn't a terminal.
+		`,
 		RunE:    ec.delete,
 		PreRunE: ec.bindViperFlagsPreRun,
 	}
 
-	cmd.Flags().String("type", "", "Filter by errand type")
-	cmd.Flags().String("status", "failed", "Filter by status; comma delimited")
+	addErrandFilterFlags(cmd, "failed")
 	cmd.Flags().String("id", "", "ID of the errand to delete")
 	cmd.Flags().Bool("dry-run", false, "Don't actually delete anything. Only used for bulk deletion")
+	cmd.Flags().BoolP("yes", "y", false, "Don't ask for confirmation before deleting errands in bulk")
+	cmd.Flags().Int("concurrency", 4, "Max concurrent deletes")
 
 	return cmd, nil
 }
@@ -29,54 +38,90 @@ func (ec *errandsCmd) delete(cmd *cobra.Command, args []string) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	if ec.viper.GetBool("bootstrap") {
-		portCmd, err := ec.portForwardErrandsServer(ctx)
-		if err != nil {
-			return fmt.Errorf("port-forward: %w", err)
-		}
+	p, err := ec.printer()
+	if err != nil {
+		return err
+	}
 
-		defer func() {
-			if err := portCmd.Process.Signal(os.Interrupt); err != nil {
-				fmt.Printf("error killing port-forward: %s\n", err)
-			}
-		}()
+	filter, err := ec.errandFilter()
+	if err != nil {
+		return err
 	}
 
+	stopPortForward, err := ec.startPortForward(ctx)
+	if err != nil {
+		return err
+	}
+	defer stopPortForward()
+
 	if id := ec.viper.GetString("id"); id != "" {
-		if err := deleteErrand(ec.api, id); err != nil {
+		if err := deleteErrand(ctx, ec.api, id); err != nil {
 			return fmt.Errorf("failed to delete errand %s: %w", id, err)
 		}
 
 		return nil
 	}
 
-	jobs, err := listErrandsForTopic(ec.api, ec.viper.GetString("type"), ec.viper.GetString("status"))
+	// Deleting is permanent, so bulk deletes are limited to a single type.
+	if filter.errandType == "" {
+		return errors.New("--type is required to delete errands in bulk")
+	}
+
+	jobs, err := filter.list(ctx, ec.api)
 	if err != nil {
 		return fmt.Errorf("failed to get errands: %w", err)
 	}
 
-	for _, job := range jobs {
-		name := job.Name
-		if len(name) > 100 {
-			name = name[:100]
-		}
+	if ec.viper.GetBool("dry-run") {
+		fmt.Fprintf(os.Stderr, "(dry-run) %d errands would be deleted\n", len(jobs))
+		return p.printList(os.Stdout, errandItems(jobs), errandColumns)
+	}
+
+	if len(jobs) == 0 {
+		fmt.Fprintln(os.Stderr, "no errands match")
+		return nil
+	}
 
-		if ec.viper.GetBool("dry-run") {
-			fmt.Printf("(dry-run) delete %s: (%s) %s\n", job.ID, job.Status, name)
-			continue
+	if !ec.viper.GetBool("yes") {
+		confirmed, err := confirmBulk(jobs, "delete", "deleted")
+		if err != nil {
+			return err
 		}
 
-		fmt.Printf("deleting %s\n", job.ID)
-		if err := deleteErrand(ec.api, job.ID); err != nil {
-			fmt.Printf("failed to delete errand %s: %e", job.ID, err)
+		if !confirmed {
+			fmt.Fprintln(os.Stderr, "aborted; nothing was deleted")
+			return nil
 		}
 	}
 
+	bar := newProgressBar(os.Stderr, len(jobs))
+	result := forEachErrand(ctx, jobs, ec.viper.GetInt("concurrency"), func(ctx context.Context, job schemas.Errand) error {
+		defer bar.increment()
+		return deleteErrand(ctx, ec.api, job.ID)
+	})
+	bar.finish()
+
+	for id, err := range result.failed {
+		fmt.Printf("failed to delete errand %s: %s\n", id, err)
+	}
+	fmt.Printf("deleted %d of %d errands, %d failed\n", result.succeeded, len(jobs), len(result.failed))
+
+	if len(result.failed) > 0 {
+		return fmt.Errorf("failed to delete %d errands", len(result.failed))
+	}
+
 	return nil
 }
 
-func deleteErrand(api *errandz.ErrandsAPI, id string) error {
-	_, err := api.DeleteErrand(id)
+func deleteErrand(ctx context.Context, api *errandz.ErrandsAPI, id string) error {
+	res, err := api.DeleteErrandContext(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if res.Status != "OK" {
+		return fmt.Errorf("response status %q", res.Status)
+	}
 
-	return err
+	return nil
 }
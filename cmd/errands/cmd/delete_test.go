@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// deleteServer serves errands of type sort for bulk deletes, failing to delete the ones in fail.
+type deleteServer struct {
+	fail map[string]bool
+
+	mu      sync.Mutex
+	deleted []string
+}
+
+func (s *deleteServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/v1/errands/list/type/sort":
+		w.Write([]byte(`{"status":"OK","results":[
+			{"id":"a","name":"a","type":"sort","status":"failed"},
+			{"id":"b","name":"b","type":"sort","status":"failed"},
+			{"id":"c","name":"c","type":"sort","status":"failed"}]}`))
+	case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/v1/errand/"):
+		id := strings.TrimPrefix(r.URL.Path, "/v1/errand/")
+		if s.fail[id] {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"message":"Internal Server Error!","error":"errand not found"}`))
+			return
+		}
+
+		s.mu.Lock()
+		s.deleted = append(s.deleted, id)
+		s.mu.Unlock()
+		w.Write([]byte(`{"status":"OK"}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// withStdin makes stdin read input, and a terminal if tty is set, for the rest of the test.
+func withStdin(t *testing.T, tty bool, input string) {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.WriteString(input); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+
+	stdin, isTerminal := os.Stdin, stdinIsTerminal
+	os.Stdin = r
+	stdinIsTerminal = func() bool { return tty }
+	t.Cleanup(func() {
+		os.Stdin, stdinIsTerminal = stdin, isTerminal
+		r.Close()
+	})
+}
+
+func TestDelete(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		tty     bool
+		input   string
+		fail    []string
+		err     string
+		deleted []string
+		output  string
+	}{
+		{
+			name: "no type",
+			args: []string{"--status=failed"},
+			err:  "--type is required to delete errands in bulk",
+		},
+		{
+			name: "stdin isn't a terminal",
+			args: []string{"--type=sort"},
+			err:  "refusing to delete 3 errands without confirmation; pass --yes to delete them anyway",
+		},
+		{
+			name:    "confirmed",
+			args:    []string{"--type=sort"},
+			tty:     true,
+			input:   "y\n",
+			deleted: []string{"a", "b", "c"},
+			output:  "deleted 3 of 3 errands, 0 failed\n",
+		},
+		{
+			name:  "declined",
+			args:  []string{"--type=sort"},
+			tty:   true,
+			input: "n\n",
+		},
+		{
+			name: "no answer",
+			args: []string{"--type=sort"},
+			tty:  true,
+		},
+		{
+			name:    "yes",
+			args:    []string{"--type=sort", "--yes"},
+			deleted: []string{"a", "b", "c"},
+			output:  "deleted 3 of 3 errands, 0 failed\n",
+		},
+		{
+			name:    "some deletes fail",
+			args:    []string{"--type=sort", "--yes"},
+			fail:    []string{"b"},
+			err:     "failed to delete 1 errands",
+			deleted: []string{"a", "c"},
+			output:  "failed to delete errand b: response status \"\"\ndeleted 2 of 3 errands, 1 failed\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := &deleteServer{fail: make(map[string]bool)}
+			for _, id := range test.fail {
+				server.fail[id] = true
+			}
+			ts := httptest.NewServer(server)
+			defer ts.Close()
+
+			withStdin(t, test.tty, test.input)
+
+			args := append([]string{"delete", "--bootstrap=false", "--endpoint=" + ts.URL}, test.args...)
+			output, err := runErrands(t, "", args...)
+			if test.err == "" && err != nil {
+				t.Fatal(err)
+			}
+			if test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)) {
+				t.Errorf("expected error containing %q, got %v", test.err, err)
+			}
+
+			sort.Strings(server.deleted)
+			if strings.Join(server.deleted, ",") != strings.Join(test.deleted, ",") {
+				t.Errorf("expected %v to be deleted, got %v", test.deleted, server.deleted)
+			}
+			if output != test.output {
+				t.Errorf("expected output %q, got %q", test.output, output)
+			}
+		})
+	}
+}
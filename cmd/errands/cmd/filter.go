@@ -0,0 +1,261 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	errandz "github.com/polygon-io/errands-go"
+	"github.com/polygon-io/errands-server/schemas"
+	"github.com/spf13/cobra"
+)
+
+// errandSortFields are the fields errands can be sorted by with --sort.
+var errandSortFields = map[string]func(schemas.Errand) interface{}{
+	"created":  func(e schemas.Errand) interface{} { return e.Created },
+	"started":  func(e schemas.Errand) interface{} { return e.Started },
+	"failed":   func(e schemas.Errand) interface{} { return e.Failed },
+	"name":     func(e schemas.Errand) interface{} { return e.Name },
+	"status":   func(e schemas.Errand) interface{} { return string(e.Status) },
+	"attempts": func(e schemas.Errand) interface{} { return e.Attempts },
+}
+
+// addErrandFilterFlags adds the flags for selecting errands that are shared by list, delete and retry.
+func addErrandFilterFlags(cmd *cobra.Command, defaultStatus string) {
+	cmd.Flags().String("type", "", "Filter by errand type")
+	cmd.Flags().String("status", defaultStatus, "Filter by status; comma delimited")
+	cmd.Flags().String("older-than", "", "Only errands whose --time-field is longer ago than this, like 2h or 7d")
+	cmd.Flags().String("since", "", "Only errands whose --time-field is after this; RFC 3339 or a duration ago, like 2h")
+	cmd.Flags().String("time-field", "created", "Timestamp --older-than and --since apply to: created, started or failed")
+	cmd.Flags().String("name", "", "Only errands whose name matches this regular expression")
+	cmd.Flags().StringArray("where", nil, "Only errands with a data or results field equal to a value, like data.ticker=AAPL; can be repeated")
+	cmd.Flags().String("failure-reason", "", "Only errands whose failure reason contains this text")
+	cmd.Flags().Int("min-attempts", 0, "Only errands attempted at least this many times")
+	cmd.Flags().Int("max-attempts", -1, "Only errands attempted at most this many times")
+	cmd.Flags().Int("limit", 0, "Max number of errands; 0 for no limit")
+	cmd.Flags().String("sort", "-created", "Sort by created, started, failed, name, status or attempts; prefix with - for descending")
+}
+
+// errandFilter selects errands with the filter flags.
+type errandFilter struct {
+	errandType string
+	statuses   map[schemas.Status]bool
+
+	timeField     string
+	before, after time.Time
+
+	name          *regexp.Regexp
+	where         []fieldMatch
+	failureReason string
+	minAttempts   int
+	maxAttempts   int
+
+	limit      int
+	sortField  string
+	descending bool
+}
+
+// fieldMatch matches errands with a data or results field, given as a dotted path, equal to a value.
+type fieldMatch struct {
+	path  []string
+	value string
+}
+
+// errandFilter builds a filter from the filter flags.
+func (ec *errandsCmd) errandFilter() (*errandFilter, error) {
+	f := &errandFilter{
+		errandType:    ec.viper.GetString("type"),
+		timeField:     ec.viper.GetString("time-field"),
+		failureReason: ec.viper.GetString("failure-reason"),
+		minAttempts:   ec.viper.GetInt("min-attempts"),
+		maxAttempts:   ec.viper.GetInt("max-attempts"),
+		limit:         ec.viper.GetInt("limit"),
+	}
+
+	if status := ec.viper.GetString("status"); status != "" {
+		f.statuses = make(map[schemas.Status]bool)
+		for _, s := range strings.Split(status, ",") {
+			f.statuses[schemas.Status(s)] = true
+		}
+	}
+
+	switch f.timeField {
+	case "created", "started", "failed":
+	default:
+		return nil, fmt.Errorf("invalid --time-field %q; expected created, started or failed", f.timeField)
+	}
+
+	if olderThan := ec.viper.GetString("older-than"); olderThan != "" {
+		d, err := parseDurationFlag(olderThan)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --older-than: %w", err)
+		}
+		f.before = time.Now().Add(-d)
+	}
+
+	since, err := parseTimeFlag(ec.viper.GetString("since"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid --since: %w", err)
+	}
+	f.after = since
+
+	if name := ec.viper.GetString("name"); name != "" {
+		if f.name, err = regexp.Compile(name); err != nil {
+			return nil, fmt.Errorf("invalid --name: %w", err)
+		}
+	}
+
+	for _, w := range ec.viper.GetStringSlice("where") {
+		path, value, ok := strings.Cut(w, "=")
+		if !ok || !(strings.HasPrefix(path, "data.") || strings.HasPrefix(path, "results.")) {
+			return nil, fmt.Errorf("invalid --where %q; expected data.<field>=<value> or results.<field>=<value>", w)
+		}
+		f.where = append(f.where, fieldMatch{path: strings.Split(path, "."), value: value})
+	}
+
+	f.sortField = strings.TrimPrefix(ec.viper.GetString("sort"), "-")
+	f.descending = f.sortField != ec.viper.GetString("sort")
+	if _, ok := errandSortFields[f.sortField]; !ok {
+		return nil, fmt.Errorf("invalid --sort %q; expected created, started, failed, name, status or attempts", f.sortField)
+	}
+
+	return f, nil
+}
+
+// list fetches the errands matching the filter, sorted and limited.
+// It narrows the request down by type, or by status if only one is given, and filters the rest client-side.
+func (f *errandFilter) list(ctx context.Context, api *errandz.ErrandsAPI) ([]schemas.Errand, error) {
+	var (
+		res *errandz.ErrandsResponse
+		err error
+	)
+
+	switch {
+	case f.errandType != "":
+		res, err = api.ListErrandsContext(ctx, "type", f.errandType)
+	case len(f.statuses) == 1:
+		for status := range f.statuses {
+			res, err = api.ListErrandsContext(ctx, "status", string(status))
+		}
+	default:
+		res, err = api.GetErrandsContext(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var jobs []schemas.Errand
+	for _, job := range res.Results {
+		if f.matches(&job) {
+			jobs = append(jobs, job)
+		}
+	}
+
+	key := errandSortFields[f.sortField]
+	sort.SliceStable(jobs, func(i, j int) bool {
+		if f.descending {
+			i, j = j, i
+		}
+		return lessValue(key(jobs[i]), key(jobs[j]))
+	})
+
+	if f.limit > 0 && len(jobs) > f.limit {
+		jobs = jobs[:f.limit]
+	}
+
+	return jobs, nil
+}
+
+func (f *errandFilter) matches(job *schemas.Errand) bool {
+	if f.errandType != "" && job.Type != f.errandType {
+		return false
+	}
+
+	if f.statuses != nil && !f.statuses[job.Status] {
+		return false
+	}
+
+	if !f.before.IsZero() || !f.after.IsZero() {
+		var millis int64
+		switch f.timeField {
+		case "created":
+			millis = job.Created
+		case "started":
+			millis = job.Started
+		case "failed":
+			millis = job.Failed
+		}
+
+		// Errands that haven't started or failed yet don't have a time to compare.
+		if millis == 0 {
+			return false
+		}
+
+		t := time.UnixMilli(millis)
+		if !f.before.IsZero() && !t.Before(f.before) {
+			return false
+		}
+		if !f.after.IsZero() && !t.After(f.after) {
+			return false
+		}
+	}
+
+	if f.name != nil && !f.name.MatchString(job.Name) {
+		return false
+	}
+
+	for _, match := range f.where {
+		fields := job.Data
+		if match.path[0] == "results" {
+			fields = job.Results
+		}
+
+		value, ok := lookupField(fields, match.path[1:])
+		if !ok || fmt.Sprint(value) != match.value {
+			return false
+		}
+	}
+
+	if f.failureReason != "" && !strings.Contains(errandz.FailureReason(job), f.failureReason) {
+		return false
+	}
+
+	if job.Attempts < f.minAttempts || (f.maxAttempts >= 0 && job.Attempts > f.maxAttempts) {
+		return false
+	}
+
+	return true
+}
+
+// lookupField returns the value at a path of keys in nested maps.
+func lookupField(fields map[string]interface{}, path []string) (interface{}, bool) {
+	var value interface{} = fields
+	for _, key := range path {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+
+		if value, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+
+	return value, true
+}
+
+func lessValue(a, b interface{}) bool {
+	switch a := a.(type) {
+	case int64:
+		return a < b.(int64)
+	case int:
+		return a < b.(int)
+	case string:
+		return a < b.(string)
+	default:
+		return false
+	}
+}
@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	errandz "github.com/polygon-io/errands-go"
+	"github.com/polygon-io/errands-server/schemas"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// parseFlags parses args into cmd's flags and binds them to a new errandsCmd's viper, like bindViperFlagsPreRun
+// does, without the config file, port check or API.
+func parseFlags(t *testing.T, cmd *cobra.Command, args ...string) *errandsCmd {
+	t.Helper()
+
+	if err := cmd.ParseFlags(args); err != nil {
+		t.Fatal(err)
+	}
+
+	ec := &errandsCmd{viper: viper.New()}
+	if err := ec.viper.BindPFlags(cmd.Flags()); err != nil {
+		t.Fatal(err)
+	}
+
+	return ec
+}
+
+func newFilterCommand() *cobra.Command {
+	cmd := &cobra.Command{Use: "list"}
+	addErrandFilterFlags(cmd, "")
+
+	return cmd
+}
+
+func TestErrandFilter(t *testing.T) {
+	now := time.Now()
+	ago := func(d time.Duration) int64 { return now.Add(-d).UnixMilli() }
+
+	jobs := []schemas.Errand{
+		{
+			ID: "a", Name: "backfill-2022-01", Type: "sort", Status: schemas.StatusFailed, Attempts: 3,
+			Created: ago(3 * time.Hour), Started: ago(2 * time.Hour), Failed: ago(time.Hour),
+			Data: map[string]interface{}{"ticker": "AAPL", "window": map[string]interface{}{"days": 5.0}},
+			Logs: []schemas.Log{{Severity: "ERROR", Message: "timeout talking to s3"}},
+		},
+		{
+			ID: "b", Name: "backfill-2022-02", Type: "sort", Status: schemas.StatusInactive,
+			Created: ago(30 * time.Minute),
+			Data:    map[string]interface{}{"ticker": "MSFT"},
+		},
+		{
+			ID: "c", Name: "daily", Type: "sort", Status: schemas.StatusCompleted, Attempts: 1,
+			Created: ago(50 * time.Hour), Started: ago(49 * time.Hour),
+			Data:    map[string]interface{}{"ticker": "AAPL"},
+			Results: map[string]interface{}{"rows": 10.0},
+		},
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{name: "defaults sort newest first", want: []string{"b", "a", "c"}},
+		{name: "status", args: []string{"--status=failed,completed"}, want: []string{"a", "c"}},
+		{name: "older than", args: []string{"--older-than=2h"}, want: []string{"a", "c"}},
+		{name: "older than days", args: []string{"--older-than=2d"}, want: []string{"c"}},
+		{name: "since duration", args: []string{"--since=4h"}, want: []string{"b", "a"}},
+		{name: "since timestamp", args: []string{"--since=" + now.Add(-4*time.Hour).Format(time.RFC3339)}, want: []string{"b", "a"}},
+		// b hasn't started, so it has no time to compare and is left out.
+		{name: "time field", args: []string{"--time-field=started", "--since=3h"}, want: []string{"a"}},
+		{name: "older than and since", args: []string{"--older-than=1h", "--since=10h"}, want: []string{"a"}},
+		{name: "name", args: []string{"--name=^backfill-"}, want: []string{"b", "a"}},
+		{name: "where data", args: []string{"--where", "data.ticker=AAPL"}, want: []string{"a", "c"}},
+		{name: "where nested data", args: []string{"--where", "data.window.days=5"}, want: []string{"a"}},
+		{name: "where results", args: []string{"--where", "results.rows=10"}, want: []string{"c"}},
+		{name: "where repeated", args: []string{"--where", "data.ticker=AAPL", "--where", "results.rows=10"}, want: []string{"c"}},
+		{name: "where missing field", args: []string{"--where", "data.missing=x"}, want: nil},
+		{name: "failure reason", args: []string{"--failure-reason=timeout"}, want: []string{"a"}},
+		{name: "min attempts", args: []string{"--min-attempts=1"}, want: []string{"a", "c"}},
+		{name: "max attempts", args: []string{"--max-attempts=1"}, want: []string{"b", "c"}},
+		{name: "max attempts zero", args: []string{"--max-attempts=0"}, want: []string{"b"}},
+		{name: "sort ascending", args: []string{"--sort=created"}, want: []string{"c", "a", "b"}},
+		{name: "sort by attempts", args: []string{"--sort=-attempts"}, want: []string{"a", "c", "b"}},
+		{name: "sort by name", args: []string{"--sort=name"}, want: []string{"a", "b", "c"}},
+		{name: "limit", args: []string{"--sort=name", "--limit=2"}, want: []string{"a", "b"}},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(&errandz.ErrandsResponse{Status: "OK", Results: jobs})
+	}))
+	defer server.Close()
+
+	api := errandz.New(server.URL)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filter, err := parseFlags(t, newFilterCommand(), test.args...).errandFilter()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			matched, err := filter.list(context.Background(), api)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var ids []string
+			for _, job := range matched {
+				ids = append(ids, job.ID)
+			}
+			if !reflect.DeepEqual(ids, test.want) {
+				t.Errorf("expected %v, got %v", test.want, ids)
+			}
+		})
+	}
+}
+
+func TestErrandFilterNarrowsRequest(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		path string
+	}{
+		{name: "everything", path: "/v1/errands/"},
+		{name: "type", args: []string{"--type=sort", "--status=failed"}, path: "/v1/errands/list/type/sort"},
+		{name: "one status", args: []string{"--status=failed"}, path: "/v1/errands/list/status/failed"},
+		{name: "several statuses", args: []string{"--status=failed,inactive"}, path: "/v1/errands/"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var path string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				path = r.URL.Path
+				w.Write([]byte(`{"status":"OK","results":[]}`))
+			}))
+			defer server.Close()
+
+			filter, err := parseFlags(t, newFilterCommand(), test.args...).errandFilter()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := filter.list(context.Background(), errandz.New(server.URL)); err != nil {
+				t.Fatal(err)
+			}
+			if path != test.path {
+				t.Errorf("expected a request to %s, got %s", test.path, path)
+			}
+		})
+	}
+}
+
+func TestErrandFilterErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		err  string
+	}{
+		{name: "time field", args: []string{"--time-field=completed"}, err: `invalid --time-field "completed"`},
+		{name: "older than", args: []string{"--older-than=soon"}, err: "invalid --older-than"},
+		{name: "older than days", args: []string{"--older-than=xd"}, err: "invalid --older-than"},
+		{name: "since", args: []string{"--since=yesterday"}, err: "invalid --since"},
+		{name: "name", args: []string{"--name=("}, err: "invalid --name"},
+		{name: "where without value", args: []string{"--where", "data.ticker"}, err: `invalid --where "data.ticker"`},
+		{name: "where without prefix", args: []string{"--where", "ticker=AAPL"}, err: `invalid --where "ticker=AAPL"`},
+		{name: "sort", args: []string{"--sort=-priority"}, err: `invalid --sort "priority"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := parseFlags(t, newFilterCommand(), test.args...).errandFilter()
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("expected error containing %q, got %v", test.err, err)
+			}
+		})
+	}
+}
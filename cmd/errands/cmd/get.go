@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	errandz "github.com/polygon-io/errands-go"
+	"github.com/polygon-io/errands-server/schemas"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+func (ec *errandsCmd) newGetCommand() (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:   "get <errand-id>",
+		Short: "shows a single errand in detail",
+		Long: `
+		Shows every field of an errand, including its data and logs. The errands server has no route for
+		getting a single errand, so this fetches every errand and picks out the one with the given ID.
+		`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: ec.bindViperFlagsPreRun,
+		RunE:    ec.get,
+	}
+
+	return cmd, nil
+}
+
+func (ec *errandsCmd) get(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p, err := ec.printer()
+	if err != nil {
+		return err
+	}
+
+	stopPortForward, err := ec.startPortForward(ctx)
+	if err != nil {
+		return err
+	}
+	defer stopPortForward()
+
+	res, err := ec.api.GetErrand(ctx, args[0])
+	if errors.Is(err, errandz.ErrErrandNotFound) {
+		return fmt.Errorf("errand %s not found", args[0])
+	}
+	if err != nil {
+		return fmt.Errorf("get errand: %w", err)
+	}
+
+	return p.printOne(os.Stdout, res.Results, errandColumns, func(w io.Writer) error {
+		return writeErrandDetails(w, &res.Results)
+	})
+}
+
+// writeErrandDetails writes every field of an errand in a layout meant for reading.
+func writeErrandDetails(w io.Writer, errand *schemas.Errand) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "ID:\t%s\n", errand.ID)
+	fmt.Fprintf(tw, "Name:\t%s\n", errand.Name)
+	fmt.Fprintf(tw, "Type:\t%s\n", errand.Type)
+	fmt.Fprintf(tw, "Status:\t%s\n", errand.Status)
+	fmt.Fprintf(tw, "Progress:\t%.0f%%\n", errand.Progress)
+	fmt.Fprintf(tw, "Attempts:\t%d\n", errand.Attempts)
+	if errand.PipelineID != "" {
+		fmt.Fprintf(tw, "Pipeline:\t%s\n", errand.PipelineID)
+	}
+
+	for _, timestamp := range []struct {
+		label  string
+		millis int64
+	}{
+		{"Created", errand.Created},
+		{"Started", errand.Started},
+		{"Failed", errand.Failed},
+		{"Completed", errand.Completed},
+	} {
+		if timestamp.millis != 0 {
+			fmt.Fprintf(tw, "%s:\t%s\n", timestamp.label, time.UnixMilli(timestamp.millis).Format(time.RFC3339))
+		}
+	}
+
+	fmt.Fprintf(tw, "Options:\tttl=%d retries=%d priority=%d deleteOnCompleted=%t\n",
+		errand.Options.TTL, errand.Options.Retries, errand.Options.Priority, errand.Options.DeleteOnCompleted)
+
+	if reason := errandz.FailureReason(errand); reason != "" && errand.Status == schemas.StatusFailed {
+		fmt.Fprintf(tw, "Failure reason:\t%s\n", reason)
+	}
+
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if err := writeIndentedYAML(w, "Data", errand.Data); err != nil {
+		return err
+	}
+
+	if err := writeIndentedYAML(w, "Results", errand.Results); err != nil {
+		return err
+	}
+
+	if len(errand.Logs) > 0 {
+		fmt.Fprintln(w, "Logs:")
+		tw = tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		for _, log := range errand.Logs {
+			timestamp := ""
+			if log.Timestamp != 0 {
+				timestamp = time.UnixMilli(log.Timestamp).Format(time.RFC3339)
+			}
+			fmt.Fprintf(tw, "  %s\t%s\t%s\n", timestamp, log.Severity, log.Message)
+		}
+
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeIndentedYAML writes a labelled map as YAML indented under the label, or nothing if it's empty.
+func writeIndentedYAML(w io.Writer, label string, m map[string]interface{}) error {
+	if len(m) == 0 {
+		return nil
+	}
+
+	var b strings.Builder
+	encoder := yaml.NewEncoder(&b)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(m); err != nil {
+		return fmt.Errorf("marshal %s: %w", strings.ToLower(label), err)
+	}
+
+	fmt.Fprintf(w, "%s:\n", label)
+	for _, line := range strings.Split(strings.TrimRight(b.String(), "\n"), "\n") {
+		fmt.Fprintf(w, "  %s\n", line)
+	}
+
+	return nil
+}
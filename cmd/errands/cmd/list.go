@@ -2,11 +2,9 @@ package cmd
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"os"
-	"sort"
-	"strings"
+	"strconv"
 	"time"
 
 	errandz "github.com/polygon-io/errands-go"
@@ -22,8 +20,7 @@ func (ec *errandsCmd) newListCommand() (*cobra.Command, error) {
 		RunE:    ec.run,
 	}
 
-	cmd.Flags().String("type", "", "filter by errand type")
-	cmd.Flags().String("status", "", "filter by status; comma delimited")
+	addErrandFilterFlags(cmd, "")
 	cmd.Flags().Int("port", 5555, "localhost port for the errands server")
 
 	return cmd, nil
@@ -33,70 +30,65 @@ func (ec *errandsCmd) run(cmd *cobra.Command, args []string) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	if ec.viper.GetBool("bootstrap") {
-		portCmd, err := ec.portForwardErrandsServer(ctx)
-		if err != nil {
-			return fmt.Errorf("port-forward: %w", err)
-		}
-
-		defer func() {
-			if err := portCmd.Process.Signal(os.Interrupt); err != nil {
-				fmt.Printf("error killing port-forward: %s\n", err)
-			}
-		}()
+	p, err := ec.printer()
+	if err != nil {
+		return err
 	}
 
-	jobs, err := listErrandsForTopic(ec.api, ec.viper.GetString("type"), ec.viper.GetString("status"))
+	filter, err := ec.errandFilter()
 	if err != nil {
-		return fmt.Errorf("get errands: %w", err)
+		return err
 	}
 
-	for _, job := range jobs {
-		name := job.Name
-		if len(name) > 100 {
-			name = name[:100]
-		}
-		fmt.Printf("%100s: %10s | %s | %s\n", name, job.Status, job.ID, time.UnixMilli(job.Created))
+	stopPortForward, err := ec.startPortForward(ctx)
+	if err != nil {
+		return err
 	}
+	defer stopPortForward()
 
-	return nil
-}
-
-func filterByStatus(jobs []schemas.Errand, status string) []schemas.Errand {
-	if status == "" {
-		return jobs
+	jobs, err := filter.list(ctx, ec.api)
+	if err != nil {
+		return fmt.Errorf("get errands: %w", err)
 	}
 
-	statusFilter := make(map[schemas.Status]bool)
-	for _, s := range strings.Split(status, ",") {
-		statusFilter[schemas.Status(s)] = true
-	}
+	return p.printList(os.Stdout, errandItems(jobs), errandColumns)
+}
 
-	var filtered []schemas.Errand
-	for _, job := range jobs {
-		if statusFilter[job.Status] {
-			filtered = append(filtered, job)
+// errandColumns are the columns errands are shown with in table, wide and csv output.
+var errandColumns = []column{
+	{header: "ID", value: func(item outputItem) string { return item.(schemas.Errand).ID }},
+	{header: "NAME", value: func(item outputItem) string { return item.(schemas.Errand).Name }},
+	{header: "TYPE", wide: true, value: func(item outputItem) string { return item.(schemas.Errand).Type }},
+	{header: "STATUS", value: func(item outputItem) string { return string(item.(schemas.Errand).Status) }},
+	{header: "CREATED", value: func(item outputItem) string { return formatMillis(item.(schemas.Errand).Created) }},
+	{header: "ATTEMPTS", wide: true, value: func(item outputItem) string { return strconv.Itoa(item.(schemas.Errand).Attempts) }},
+	{header: "PROGRESS", wide: true, value: func(item outputItem) string {
+		return strconv.FormatFloat(item.(schemas.Errand).Progress, 'f', -1, 64)
+	}},
+	{header: "PIPELINE", wide: true, value: func(item outputItem) string { return item.(schemas.Errand).PipelineID }},
+	{header: "FAILURE REASON", wide: true, value: func(item outputItem) string {
+		errand := item.(schemas.Errand)
+		if errand.Status != schemas.StatusFailed {
+			return ""
 		}
-	}
-
-	return filtered
+		return errandz.FailureReason(&errand)
+	}},
 }
 
-func listErrandsForTopic(api *errandz.ErrandsAPI, errandType string, status string) ([]schemas.Errand, error) {
-	if errandType == "" {
-		return nil, errors.New("errand type is required")
+func errandItems(jobs []schemas.Errand) []outputItem {
+	items := make([]outputItem, len(jobs))
+	for i, job := range jobs {
+		items[i] = job
 	}
 
-	jobs, err := api.ListErrands("type", errandType)
-	if err != nil {
-		return nil, err
-	}
-
-	results := filterByStatus(jobs.Results, status)
+	return items
+}
 
-	sort.Slice(results, func(i, j int) bool {
-		return results[i].Created > results[j].Created
-	})
+// formatMillis formats a server timestamp in milliseconds, or returns an empty string if it isn't set.
+func formatMillis(millis int64) string {
+	if millis == 0 {
+		return ""
+	}
 
-	return results, nil
+	return time.UnixMilli(millis).Format(time.RFC3339)
 }
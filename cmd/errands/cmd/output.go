@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// outputFormats are the formats accepted by --output, besides a Go template given as go-template=<template>.
+var outputFormats = []string{"table", "wide", "json", "jsonl", "yaml", "csv"}
+
+// printer writes items in the format chosen with --output.
+type printer struct {
+	format   string
+	template *template.Template
+}
+
+// outputItem is anything that can be printed, like a schemas.Errand or schemas.Pipeline.
+type outputItem interface {
+	MarshalJSON() ([]byte, error)
+}
+
+// column is a column of table, wide and csv output.
+type column struct {
+	header string
+
+	// wide columns are only shown in wide and csv output.
+	wide bool
+
+	value func(item outputItem) string
+}
+
+func newPrinter(output string) (*printer, error) {
+	if tmpl := strings.TrimPrefix(output, "go-template="); tmpl != output {
+		t, err := template.New("output").Parse(tmpl)
+		if err != nil {
+			return nil, fmt.Errorf("parse output template: %w", err)
+		}
+
+		return &printer{format: "go-template", template: t}, nil
+	}
+
+	if !containsString(outputFormats, output) {
+		return nil, fmt.Errorf("unknown output format %q; expected one of %s or go-template=<template>", output, strings.Join(outputFormats, ", "))
+	}
+
+	return &printer{format: output}, nil
+}
+
+// printer returns a printer for the --output flag.
+func (ec *errandsCmd) printer() (*printer, error) {
+	return newPrinter(ec.viper.GetString("output"))
+}
+
+// printList writes a list of items. Table output shows columns that aren't wide; wide and csv output show them all.
+func (p *printer) printList(w io.Writer, items []outputItem, columns []column) error {
+	switch p.format {
+	case "table", "wide":
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		columns = p.visibleColumns(columns)
+
+		headers := make([]string, len(columns))
+		for i, c := range columns {
+			headers[i] = c.header
+		}
+		fmt.Fprintln(tw, strings.Join(headers, "\t"))
+
+		for _, item := range items {
+			fmt.Fprintln(tw, strings.Join(columnValues(item, columns), "\t"))
+		}
+
+		return tw.Flush()
+
+	case "csv":
+		cw := csv.NewWriter(w)
+
+		headers := make([]string, len(columns))
+		for i, c := range columns {
+			headers[i] = strings.ToLower(strings.ReplaceAll(c.header, " ", "_"))
+		}
+		if err := cw.Write(headers); err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			if err := cw.Write(columnValues(item, columns)); err != nil {
+				return err
+			}
+		}
+
+		cw.Flush()
+		return cw.Error()
+
+	case "json", "yaml":
+		var b bytes.Buffer
+		b.WriteByte('[')
+		for i, item := range items {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+
+			itemBytes, err := item.MarshalJSON()
+			if err != nil {
+				return fmt.Errorf("marshal output: %w", err)
+			}
+			b.Write(itemBytes)
+		}
+		b.WriteByte(']')
+
+		if p.format == "yaml" {
+			return writeYAML(w, b.Bytes())
+		}
+		return writeJSON(w, b.Bytes())
+
+	default:
+		for _, item := range items {
+			if err := p.printItem(w, item); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// printOne writes a single item. Table and wide output use details, which shows the item in a layout meant for reading.
+func (p *printer) printOne(w io.Writer, item outputItem, columns []column, details func(io.Writer) error) error {
+	switch p.format {
+	case "table", "wide":
+		return details(w)
+	case "csv":
+		return p.printList(w, []outputItem{item}, columns)
+	default:
+		return p.printItem(w, item)
+	}
+}
+
+// printItem writes an item in a format that doesn't need to know about the other items.
+func (p *printer) printItem(w io.Writer, item outputItem) error {
+	if p.format == "go-template" {
+		if err := p.template.Execute(w, item); err != nil {
+			return fmt.Errorf("execute output template: %w", err)
+		}
+
+		_, err := fmt.Fprintln(w)
+		return err
+	}
+
+	itemBytes, err := item.MarshalJSON()
+	if err != nil {
+		return fmt.Errorf("marshal output: %w", err)
+	}
+
+	switch p.format {
+	case "json":
+		return writeJSON(w, itemBytes)
+	case "yaml":
+		return writeYAML(w, itemBytes)
+	default:
+		_, err := fmt.Fprintf(w, "%s\n", itemBytes)
+		return err
+	}
+}
+
+func (p *printer) visibleColumns(columns []column) []column {
+	if p.format == "wide" {
+		return columns
+	}
+
+	var visible []column
+	for _, c := range columns {
+		if !c.wide {
+			visible = append(visible, c)
+		}
+	}
+
+	return visible
+}
+
+func columnValues(item outputItem, columns []column) []string {
+	values := make([]string, len(columns))
+	for i, c := range columns {
+		values[i] = c.value(item)
+	}
+
+	return values
+}
+
+// writeJSON writes easyjson-encoded bytes to w, indented for reading.
+func writeJSON(w io.Writer, b []byte) error {
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, b, "", "  "); err != nil {
+		return fmt.Errorf("indent json: %w", err)
+	}
+
+	indented.WriteByte('\n')
+	_, err := indented.WriteTo(w)
+	return err
+}
+
+// writeYAML writes JSON-encoded bytes to w as YAML, keeping the JSON field names and order.
+func writeYAML(w io.Writer, b []byte) error {
+	// JSON is valid YAML, so decoding it as a node keeps the field order. The nodes come out in flow style though,
+	// which is just JSON again, so reset them to block style before encoding.
+	var node yaml.Node
+	if err := yaml.Unmarshal(b, &node); err != nil {
+		return fmt.Errorf("decode json as yaml: %w", err)
+	}
+	resetYAMLStyle(&node)
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return fmt.Errorf("encode yaml: %w", err)
+	}
+
+	return encoder.Close()
+}
+
+// resetYAMLStyle clears the style of node and its children. Strings that would otherwise read as another type,
+// like "123", are still quoted by the encoder.
+func resetYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetYAMLStyle(child)
+	}
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
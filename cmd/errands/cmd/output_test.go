@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type testItem struct {
+	ID    string
+	Count int
+	Note  string
+}
+
+func (i testItem) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprintf(`{"id":%q,"count":%d}`, i.ID, i.Count)), nil
+}
+
+var testItemColumns = []column{
+	{header: "ID", value: func(item outputItem) string { return item.(testItem).ID }},
+	{header: "COUNT", value: func(item outputItem) string { return strconv.Itoa(item.(testItem).Count) }},
+	{header: "LAST NOTE", wide: true, value: func(item outputItem) string { return item.(testItem).Note }},
+}
+
+func TestPrintList(t *testing.T) {
+	items := []outputItem{
+		testItem{ID: "a", Count: 1, Note: "x y"},
+		testItem{ID: "123", Count: 22, Note: "has, comma"},
+	}
+
+	tests := []struct {
+		output string
+		items  []outputItem
+		want   string
+	}{
+		{
+			output: "table",
+			items:  items,
+			want:   "ID   COUNT\na    1\n123  22\n",
+		},
+		{
+			output: "table",
+			want:   "ID  COUNT\n",
+		},
+		{
+			output: "wide",
+			items:  items,
+			want:   "ID   COUNT  LAST NOTE\na    1      x y\n123  22     has, comma\n",
+		},
+		{
+			output: "csv",
+			items:  items,
+			want:   "id,count,last_note\na,1,x y\n123,22,\"has, comma\"\n",
+		},
+		{
+			output: "json",
+			items:  items,
+			want:   "[\n  {\n    \"id\": \"a\",\n    \"count\": 1\n  },\n  {\n    \"id\": \"123\",\n    \"count\": 22\n  }\n]\n",
+		},
+		{
+			output: "json",
+			want:   "[]\n",
+		},
+		{
+			output: "jsonl",
+			items:  items,
+			want:   "{\"id\":\"a\",\"count\":1}\n{\"id\":\"123\",\"count\":22}\n",
+		},
+		{
+			// Keys keep their JSON order, and strings that look like numbers stay strings.
+			output: "yaml",
+			items:  items,
+			want:   "- id: a\n  count: 1\n- id: \"123\"\n  count: 22\n",
+		},
+		{
+			output: "go-template={{.ID}}={{.Count}}",
+			items:  items,
+			want:   "a=1\n123=22\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%s/%d items", test.output, len(test.items)), func(t *testing.T) {
+			p, err := newPrinter(test.output)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var b bytes.Buffer
+			if err := p.printList(&b, test.items, testItemColumns); err != nil {
+				t.Fatal(err)
+			}
+			if b.String() != test.want {
+				t.Errorf("expected:\n%s\ngot:\n%s", test.want, b.String())
+			}
+		})
+	}
+}
+
+func TestPrintOne(t *testing.T) {
+	item := testItem{ID: "a", Count: 1, Note: "x y"}
+	details := func(w io.Writer) error {
+		_, err := fmt.Fprintln(w, "details of a")
+		return err
+	}
+
+	tests := []struct {
+		output string
+		want   string
+	}{
+		{output: "table", want: "details of a\n"},
+		{output: "wide", want: "details of a\n"},
+		{output: "csv", want: "id,count,last_note\na,1,x y\n"},
+		{output: "json", want: "{\n  \"id\": \"a\",\n  \"count\": 1\n}\n"},
+		{output: "jsonl", want: "{\"id\":\"a\",\"count\":1}\n"},
+		{output: "yaml", want: "id: a\ncount: 1\n"},
+		{output: "go-template={{.ID}}", want: "a\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.output, func(t *testing.T) {
+			p, err := newPrinter(test.output)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var b bytes.Buffer
+			if err := p.printOne(&b, item, testItemColumns, details); err != nil {
+				t.Fatal(err)
+			}
+			if b.String() != test.want {
+				t.Errorf("expected:\n%s\ngot:\n%s", test.want, b.String())
+			}
+		})
+	}
+}
+
+func TestNewPrinterErrors(t *testing.T) {
+	tests := []struct {
+		output string
+		err    string
+	}{
+		{output: "xml", err: `unknown output format "xml"`},
+		{output: "", err: `unknown output format ""`},
+		{output: "go-template={{.ID", err: "parse output template"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.output, func(t *testing.T) {
+			_, err := newPrinter(test.output)
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("expected error containing %q, got %v", test.err, err)
+			}
+		})
+	}
+
+	p, err := newPrinter("go-template={{.Missing}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.printList(io.Discard, []outputItem{testItem{}}, testItemColumns); err == nil || !strings.Contains(err.Error(), "execute output template") {
+		t.Errorf("expected a template execution error, got %v", err)
+	}
+}
@@ -0,0 +1,534 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	errandz "github.com/polygon-io/errands-go"
+	"github.com/polygon-io/errands-server/schemas"
+	"github.com/spf13/cobra"
+)
+
+func (ec *errandsCmd) newPipelineCommand() (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:   "pipeline",
+		Short: "commands for working with errand pipelines",
+	}
+
+	graph, err := ec.newPipelineGraphCommand()
+	if err != nil {
+		return nil, fmt.Errorf("create pipeline graph command: %w", err)
+	}
+
+	create, err := ec.newPipelineCreateCommand()
+	if err != nil {
+		return nil, fmt.Errorf("create pipeline create command: %w", err)
+	}
+
+	retry, err := ec.newPipelineRetryCommand()
+	if err != nil {
+		return nil, fmt.Errorf("create pipeline retry command: %w", err)
+	}
+
+	list, err := ec.newPipelineListCommand()
+	if err != nil {
+		return nil, fmt.Errorf("create pipeline list command: %w", err)
+	}
+
+	cleanup, err := ec.newPipelineCleanupCommand()
+	if err != nil {
+		return nil, fmt.Errorf("create pipeline cleanup command: %w", err)
+	}
+
+	progress, err := ec.newPipelineProgressCommand()
+	if err != nil {
+		return nil, fmt.Errorf("create pipeline progress command: %w", err)
+	}
+
+	cmd.AddCommand(graph)
+	cmd.AddCommand(create)
+	cmd.AddCommand(retry)
+	cmd.AddCommand(list)
+	cmd.AddCommand(cleanup)
+	cmd.AddCommand(progress)
+
+	return cmd, nil
+}
+
+func (ec *errandsCmd) newPipelineGraphCommand() (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:     "graph <pipeline-id>",
+		Short:   "renders a pipeline's dependency graph as Graphviz DOT or Mermaid",
+		Args:    cobra.ExactArgs(1),
+		PreRunE: ec.bindViperFlagsPreRun,
+		RunE:    ec.pipelineGraph,
+	}
+
+	cmd.Flags().String("format", "dot", "output format: dot or mermaid")
+
+	return cmd, nil
+}
+
+func (ec *errandsCmd) pipelineGraph(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	render := errandz.RenderPipelineDOT
+	switch format := ec.viper.GetString("format"); format {
+	case "dot":
+	case "mermaid":
+		render = errandz.RenderPipelineMermaid
+	default:
+		return fmt.Errorf("unknown format %q; expected dot or mermaid", format)
+	}
+
+	stopPortForward, err := ec.startPortForward(ctx)
+	if err != nil {
+		return err
+	}
+	defer stopPortForward()
+
+	res, err := ec.api.GetPipeline(ctx, args[0])
+	if err != nil {
+		return fmt.Errorf("get pipeline: %w", err)
+	}
+
+	if res.Status != "OK" {
+		return fmt.Errorf("get pipeline %s: response status %q", args[0], res.Status)
+	}
+
+	graph, err := render(&res.Results)
+	if err != nil {
+		return fmt.Errorf("render pipeline %s: %w", args[0], err)
+	}
+
+	fmt.Print(graph)
+
+	return nil
+}
+
+func (ec *errandsCmd) newPipelineCreateCommand() (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:     "create",
+		Short:   "creates a pipeline from a YAML or JSON spec file",
+		PreRunE: ec.bindViperFlagsPreRun,
+		RunE:    ec.pipelineCreate,
+	}
+
+	cmd.Flags().StringP("file", "f", "", "path to the pipeline spec file")
+	cmd.Flags().StringArray("var", nil, "set a spec variable as key=value; can be repeated")
+	cmd.Flags().Bool("dry-run", false, "print the pipeline that would be created instead of creating it")
+
+	return cmd, nil
+}
+
+func (ec *errandsCmd) pipelineCreate(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	file := ec.viper.GetString("file")
+	if file == "" {
+		return fmt.Errorf("--file is required")
+	}
+
+	vars := make(map[string]string)
+	for _, v := range ec.viper.GetStringSlice("var") {
+		key, value, ok := strings.Cut(v, "=")
+		if !ok {
+			return fmt.Errorf("invalid --var %q; expected key=value", v)
+		}
+		vars[key] = value
+	}
+
+	pipeline, err := errandz.LoadPipelineSpec(file, vars)
+	if err != nil {
+		return err
+	}
+
+	if ec.viper.GetBool("dry-run") {
+		pipelineBytes, err := pipeline.MarshalJSON()
+		if err != nil {
+			return fmt.Errorf("marshal pipeline: %w", err)
+		}
+
+		fmt.Println(string(pipelineBytes))
+		return nil
+	}
+
+	stopPortForward, err := ec.startPortForward(ctx)
+	if err != nil {
+		return err
+	}
+	defer stopPortForward()
+
+	res, err := ec.api.CreatePipeline(ctx, pipeline)
+	if err != nil {
+		return fmt.Errorf("create pipeline: %w", err)
+	}
+
+	if res.Results.ID == "" {
+		return fmt.Errorf("create pipeline: response status %q", res.Status)
+	}
+
+	fmt.Println(res.Results.ID)
+
+	return nil
+}
+
+func (ec *errandsCmd) newPipelineRetryCommand() (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:     "retry <pipeline-id>",
+		Short:   "retries the failed errands in a pipeline without re-running completed ones",
+		Args:    cobra.ExactArgs(1),
+		PreRunE: ec.bindViperFlagsPreRun,
+		RunE:    ec.pipelineRetry,
+	}
+
+	return cmd, nil
+}
+
+func (ec *errandsCmd) pipelineRetry(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stopPortForward, err := ec.startPortForward(ctx)
+	if err != nil {
+		return err
+	}
+	defer stopPortForward()
+
+	result, err := ec.api.RetryPipeline(ctx, args[0])
+	if result != nil {
+		for _, errand := range result.Retried {
+			fmt.Printf("retried %s (%s)\n", errand.Name, errand.ID)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("retry pipeline: %w", err)
+	}
+
+	if len(result.Retried) == 0 {
+		fmt.Println("no failed errands to retry")
+		return nil
+	}
+
+	for _, errand := range result.Blocked {
+		fmt.Printf("%s (%s) will run once its dependencies complete\n", errand.Name, errand.ID)
+	}
+
+	return nil
+}
+
+func (ec *errandsCmd) newPipelineListCommand() (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:     "list",
+		Short:   "lists pipelines, newest first",
+		PreRunE: ec.bindViperFlagsPreRun,
+		RunE:    ec.pipelineList,
+	}
+
+	cmd.Flags().String("status", "", "filter by pipeline status")
+	cmd.Flags().String("name-prefix", "", "filter by pipeline name prefix")
+	cmd.Flags().String("created-after", "", "only pipelines created after this time; RFC 3339 or a duration ago, like 24h or 7d")
+	cmd.Flags().String("created-before", "", "only pipelines created before this time; RFC 3339 or a duration ago, like 24h or 7d")
+	cmd.Flags().Int("page-size", errandz.DefaultPipelinePageSize, "number of pipelines to fetch details for at a time with --hydrate")
+	cmd.Flags().Bool("hydrate", false, "fetch each pipeline's errands and show their statuses")
+	cmd.Flags().Int("concurrency", errandz.DefaultHydrateConcurrency, "max concurrent requests when hydrating")
+
+	return cmd, nil
+}
+
+func (ec *errandsCmd) pipelineList(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p, err := ec.printer()
+	if err != nil {
+		return err
+	}
+
+	createdAfter, err := parseTimeFlag(ec.viper.GetString("created-after"))
+	if err != nil {
+		return fmt.Errorf("invalid --created-after: %w", err)
+	}
+
+	createdBefore, err := parseTimeFlag(ec.viper.GetString("created-before"))
+	if err != nil {
+		return fmt.Errorf("invalid --created-before: %w", err)
+	}
+
+	stopPortForward, err := ec.startPortForward(ctx)
+	if err != nil {
+		return err
+	}
+	defer stopPortForward()
+
+	hydrate := ec.viper.GetBool("hydrate")
+	it := ec.api.IteratePipelines(errandz.ListPipelinesOptions{
+		Status:             schemas.Status(ec.viper.GetString("status")),
+		NamePrefix:         ec.viper.GetString("name-prefix"),
+		CreatedAfter:       createdAfter,
+		CreatedBefore:      createdBefore,
+		PageSize:           ec.viper.GetInt("page-size"),
+		Hydrate:            hydrate,
+		HydrateConcurrency: ec.viper.GetInt("concurrency"),
+	})
+
+	var pipelines []*schemas.Pipeline
+	for it.Next(ctx) {
+		pipelines = append(pipelines, it.Page()...)
+	}
+
+	if err := it.Err(); err != nil {
+		return fmt.Errorf("list pipelines: %w", err)
+	}
+
+	columns := pipelineColumns
+	if hydrate {
+		columns = append(columns, column{header: "ERRANDS", value: func(item outputItem) string {
+			return errandStatusCounts(item.(*schemas.Pipeline))
+		}})
+	}
+
+	return p.printList(os.Stdout, pipelineItems(pipelines), columns)
+}
+
+// pipelineColumns are the columns pipelines are shown with in table, wide and csv output.
+var pipelineColumns = []column{
+	{header: "ID", value: func(item outputItem) string { return item.(*schemas.Pipeline).ID }},
+	{header: "NAME", value: func(item outputItem) string { return item.(*schemas.Pipeline).Name }},
+	{header: "STATUS", value: func(item outputItem) string { return string(item.(*schemas.Pipeline).Status) }},
+	{header: "STARTED", value: func(item outputItem) string { return formatMillis(item.(*schemas.Pipeline).StartedMillis) }},
+	{header: "ENDED", wide: true, value: func(item outputItem) string { return formatMillis(item.(*schemas.Pipeline).EndedMillis) }},
+	{header: "DELETE ON COMPLETED", wide: true, value: func(item outputItem) string {
+		return strconv.FormatBool(item.(*schemas.Pipeline).DeleteOnCompleted)
+	}},
+}
+
+func pipelineItems(pipelines []*schemas.Pipeline) []outputItem {
+	items := make([]outputItem, len(pipelines))
+	for i, pipeline := range pipelines {
+		items[i] = pipeline
+	}
+
+	return items
+}
+
+// errandStatusCounts summarizes a pipeline's errands like "2 completed, 1 active, 3 blocked".
+func errandStatusCounts(pipeline *schemas.Pipeline) string {
+	counts := make(map[schemas.Status]int)
+	for _, errand := range pipeline.Errands {
+		counts[errand.Status]++
+	}
+
+	var parts []string
+	for _, status := range []schemas.Status{schemas.StatusCompleted, schemas.StatusActive, schemas.StatusInactive, schemas.StatusBlocked, schemas.StatusFailed} {
+		if counts[status] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[status], status))
+		}
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// parseTimeFlag parses an RFC 3339 time, or a duration meaning that long ago. An empty value is the zero time.
+func parseTimeFlag(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if d, err := parseDurationFlag(value); err == nil {
+		return time.Now().Add(-d), nil
+	}
+
+	return time.Parse(time.RFC3339, value)
+}
+
+// parseStatuses parses a comma delimited list of the statuses pipeline cleanup accepts, completed and failed,
+// dropping repeats so each status is only acted on once.
+func parseStatuses(value string) ([]schemas.Status, error) {
+	var (
+		statuses []schemas.Status
+		seen     = make(map[schemas.Status]bool)
+	)
+
+	for _, s := range strings.Split(value, ",") {
+		status := schemas.Status(strings.TrimSpace(s))
+		if status != schemas.StatusCompleted && status != schemas.StatusFailed {
+			return nil, fmt.Errorf("can't clean up %q pipelines; expected completed or failed", status)
+		}
+
+		if !seen[status] {
+			seen[status] = true
+			statuses = append(statuses, status)
+		}
+	}
+
+	return statuses, nil
+}
+
+// parseDurationFlag parses a duration like time.ParseDuration, also accepting a number of days like 30d.
+func parseDurationFlag(value string) (time.Duration, error) {
+	if days := strings.TrimSuffix(value, "d"); days != value {
+		n, err := strconv.ParseFloat(days, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		return time.Duration(n * float64(24*time.Hour)), nil
+	}
+
+	return time.ParseDuration(value)
+}
+
+func (ec *errandsCmd) newPipelineCleanupCommand() (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:     "cleanup",
+		Short:   "deletes pipelines that ended longer ago than a retention period",
+		PreRunE: ec.bindViperFlagsPreRun,
+		RunE:    ec.pipelineCleanup,
+	}
+
+	cmd.Flags().String("older-than", "", "retention period, like 72h or 30d; required")
+	cmd.Flags().String("status", string(schemas.StatusCompleted), "pipeline statuses to clean up, completed or failed; comma delimited")
+	cmd.Flags().Bool("dry-run", false, "list the pipelines that would be deleted without deleting them")
+	cmd.Flags().Int("concurrency", errandz.DefaultCleanupConcurrency, "max concurrent deletes")
+
+	return cmd, nil
+}
+
+func (ec *errandsCmd) pipelineCleanup(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p, err := ec.printer()
+	if err != nil {
+		return err
+	}
+
+	olderThan := ec.viper.GetString("older-than")
+	if olderThan == "" {
+		return fmt.Errorf("--older-than is required")
+	}
+
+	retention, err := parseDurationFlag(olderThan)
+	if err != nil {
+		return fmt.Errorf("invalid --older-than: %w", err)
+	}
+
+	statuses, err := parseStatuses(ec.viper.GetString("status"))
+	if err != nil {
+		return fmt.Errorf("invalid --status: %w", err)
+	}
+
+	stopPortForward, err := ec.startPortForward(ctx)
+	if err != nil {
+		return err
+	}
+	defer stopPortForward()
+
+	summary, err := ec.api.CleanupPipelines(ctx, errandz.CleanupOptions{
+		OlderThan:   retention,
+		Statuses:    statuses,
+		DryRun:      ec.viper.GetBool("dry-run"),
+		Concurrency: ec.viper.GetInt("concurrency"),
+	})
+	if err != nil {
+		return err
+	}
+
+	if summary.DryRun {
+		fmt.Fprintf(os.Stderr, "(dry-run) %d pipelines would be deleted\n", len(summary.Matched))
+		return p.printList(os.Stdout, pipelineItems(summary.Matched), pipelineColumns)
+	}
+
+	for id, err := range summary.Failed {
+		fmt.Printf("failed to delete %s: %v\n", id, err)
+	}
+	fmt.Printf("deleted %d of %d pipelines, %d failed\n", len(summary.Deleted), len(summary.Matched), len(summary.Failed))
+
+	if len(summary.Failed) > 0 {
+		return fmt.Errorf("failed to delete %d pipelines", len(summary.Failed))
+	}
+
+	return nil
+}
+
+func (ec *errandsCmd) newPipelineProgressCommand() (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:     "progress <pipeline-id>",
+		Short:   "shows how far along a pipeline is, what's blocking it and when it should finish",
+		Args:    cobra.ExactArgs(1),
+		PreRunE: ec.bindViperFlagsPreRun,
+		RunE:    ec.pipelineProgress,
+	}
+
+	cmd.Flags().StringArray("estimate", nil, "expected duration of an errand type as type=duration, like load=5m; can be repeated")
+
+	return cmd, nil
+}
+
+func (ec *errandsCmd) pipelineProgress(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	estimates := make(map[string]time.Duration)
+	for _, e := range ec.viper.GetStringSlice("estimate") {
+		errandType, value, ok := strings.Cut(e, "=")
+		if !ok {
+			return fmt.Errorf("invalid --estimate %q; expected type=duration", e)
+		}
+
+		d, err := parseDurationFlag(value)
+		if err != nil {
+			return fmt.Errorf("invalid --estimate %q: %w", e, err)
+		}
+		estimates[errandType] = d
+	}
+
+	stopPortForward, err := ec.startPortForward(ctx)
+	if err != nil {
+		return err
+	}
+	defer stopPortForward()
+
+	res, err := ec.api.GetPipeline(ctx, args[0])
+	if err != nil {
+		return fmt.Errorf("get pipeline: %w", err)
+	}
+
+	if res.Status != "OK" {
+		return fmt.Errorf("get pipeline %s: response status %q", args[0], res.Status)
+	}
+
+	progress := errandz.AnalyzePipeline(&res.Results, errandz.ProgressOptions{Estimates: estimates})
+
+	fmt.Printf("%s: %s, %d of %d errands completed (%.0f%%)\n", res.Results.Name, res.Results.Status, progress.Completed, progress.Total, progress.Percent)
+
+	for _, errand := range progress.Running {
+		fmt.Printf("  running: %s (%.0f%%, started %s ago)\n", errand.Name, errand.Progress, time.Since(time.UnixMilli(errand.Started)).Round(time.Second))
+	}
+
+	for _, blocked := range progress.Blocked {
+		fmt.Printf("  blocked: %s, waiting on %s\n", blocked.Errand.Name, strings.Join(blocked.WaitingOn, ", "))
+	}
+
+	for _, errand := range progress.Failed {
+		fmt.Printf("  failed: %s\n", errand.Name)
+	}
+
+	fmt.Printf("critical path (%s): %s\n", progress.CriticalPathDuration.Round(time.Second), strings.Join(progress.CriticalPath, " -> "))
+
+	switch {
+	case res.Results.Status == schemas.StatusCompleted:
+	case progress.ETA.IsZero():
+		fmt.Println("ETA: unknown")
+	default:
+		fmt.Printf("ETA: %s (in %s)\n", progress.ETA.Format(time.RFC3339), progress.Remaining.Round(time.Second))
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/polygon-io/errands-server/schemas"
+)
+
+func TestParseStatuses(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []schemas.Status
+		err   string
+	}{
+		{value: "completed", want: []schemas.Status{schemas.StatusCompleted}},
+		{value: "completed,failed", want: []schemas.Status{schemas.StatusCompleted, schemas.StatusFailed}},
+		{value: "failed, completed,failed", want: []schemas.Status{schemas.StatusFailed, schemas.StatusCompleted}},
+		{value: "done", err: `can't clean up "done" pipelines; expected completed or failed`},
+		{value: "completed,active", err: `can't clean up "active" pipelines`},
+		{value: "blocked", err: `can't clean up "blocked" pipelines`},
+		{value: "completed,", err: `can't clean up "" pipelines`},
+		{value: "", err: `can't clean up "" pipelines`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			statuses, err := parseStatuses(test.value)
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Errorf("expected error containing %q, got %v", test.err, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(statuses, test.want) {
+				t.Errorf("expected %v, got %v", test.want, statuses)
+			}
+		})
+	}
+}
+
+func TestPipelineCleanupValidatesStatus(t *testing.T) {
+	_, err := runErrands(t, "", "pipeline", "cleanup", "--bootstrap=false", "--older-than=30d", "--status=completed,done")
+	if err == nil || !strings.Contains(err.Error(), `invalid --status: can't clean up "done" pipelines`) {
+		t.Errorf("expected an invalid --status error, got %v", err)
+	}
+}
+
+func TestPipelineCleanupRejectsActive(t *testing.T) {
+	// Pipelines that haven't ended are aged by when they were created, so this would delete running pipelines.
+	_, err := runErrands(t, "", "pipeline", "cleanup", "--bootstrap=false", "--older-than=30d", "--status=active")
+	if err == nil || !strings.Contains(err.Error(), `invalid --status: can't clean up "active" pipelines`) {
+		t.Errorf("expected an invalid --status error, got %v", err)
+	}
+}
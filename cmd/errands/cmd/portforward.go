@@ -0,0 +1,237 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// errandsServicePort is the port the errands service listens on.
+const errandsServicePort = 80
+
+// startPortForward port-forwards the errands server if bootstrapping is enabled.
+// It returns a function that stops the port-forward, which is a no-op if bootstrapping is disabled.
+func (ec *errandsCmd) startPortForward(ctx context.Context) (func(), error) {
+	if !ec.viper.GetBool("bootstrap") {
+		return func() {}, nil
+	}
+
+	stop, err := ec.portForwardErrandsServer(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("port-forward: %w", err)
+	}
+
+	return stop, nil
+}
+
+// portForwardErrandsServer forwards --port on localhost to a ready pod behind the errands service, like
+// kubectl port-forward svc/<service> does. It returns once the server answers, with a function that stops forwarding.
+func (ec *errandsCmd) portForwardErrandsServer(ctx context.Context) (func(), error) {
+	port := ec.viper.GetInt("port")
+	if port == 0 {
+		return nil, fmt.Errorf("port is required")
+	}
+
+	namespace := ec.viper.GetString("namespace")
+	service := ec.viper.GetString("service")
+
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = ec.viper.GetString("kubeconfig")
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: ec.viper.GetString("kube-context")}
+
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("load kubeconfig: %w", err)
+	}
+
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("create kubernetes client: %w", err)
+	}
+
+	pod, podPort, err := servicePod(ctx, client, namespace, service, errandsServicePort)
+	if err != nil {
+		return nil, err
+	}
+
+	transport, upgrader, err := spdy.RoundTripperFor(config)
+	if err != nil {
+		return nil, fmt.Errorf("create spdy transport: %w", err)
+	}
+
+	url := client.CoreV1().RESTClient().Post().Resource("pods").Namespace(namespace).Name(pod).SubResource("portforward").URL()
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, url)
+
+	stopCh := make(chan struct{})
+	readyCh := make(chan struct{})
+
+	// The forwarder logs every connection it handles to out, so that's discarded. Errors go to errOut.
+	forwarder, err := portforward.NewOnAddresses(dialer, []string{"localhost"}, []string{fmt.Sprintf("%d:%d", port, podPort)}, stopCh, readyCh, io.Discard, os.Stderr)
+	if err != nil {
+		return nil, fmt.Errorf("create port-forward: %w", err)
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- forwarder.ForwardPorts()
+	}()
+
+	var once sync.Once
+	stop := func() {
+		once.Do(func() {
+			close(stopCh)
+			<-errCh
+		})
+	}
+
+	select {
+	case <-readyCh:
+	case err := <-errCh:
+		return nil, fmt.Errorf("forward localhost:%d to pod %s/%s: %w", port, namespace, pod, err)
+	case <-ctx.Done():
+		stop()
+		return nil, ctx.Err()
+	}
+
+	// using the metrics endpoint to check if the server is up
+	endpoint := fmt.Sprintf("%s/metrics", ec.endpoint)
+	if err := poll(ctx, endpoint, 5*time.Second); err != nil {
+		stop()
+		return nil, fmt.Errorf("errands server in pod %s/%s: %w", namespace, pod, err)
+	}
+
+	return stop, nil
+}
+
+// servicePod picks a ready pod behind a service and works out which of its ports the service's port targets.
+func servicePod(ctx context.Context, client kubernetes.Interface, namespace, service string, servicePort int32) (string, int, error) {
+	svc, err := client.CoreV1().Services(namespace).Get(ctx, service, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return "", 0, fmt.Errorf("service %q not found in namespace %q; set --service and --namespace", service, namespace)
+	}
+	if err != nil {
+		return "", 0, fmt.Errorf("get service %s/%s: %w", namespace, service, err)
+	}
+
+	var targetPort *intstr.IntOrString
+	for _, p := range svc.Spec.Ports {
+		if p.Port == servicePort {
+			targetPort = &p.TargetPort
+			break
+		}
+	}
+	if targetPort == nil {
+		return "", 0, fmt.Errorf("service %s/%s has no port %d", namespace, service, servicePort)
+	}
+
+	if len(svc.Spec.Selector) == 0 {
+		return "", 0, fmt.Errorf("service %s/%s has no selector, so it has no pods to forward to", namespace, service)
+	}
+
+	pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(svc.Spec.Selector).String(),
+	})
+	if err != nil {
+		return "", 0, fmt.Errorf("list pods of service %s/%s: %w", namespace, service, err)
+	}
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !podReady(pod) {
+			continue
+		}
+
+		podPort, err := resolveTargetPort(pod, *targetPort, servicePort)
+		if err != nil {
+			return "", 0, fmt.Errorf("service %s/%s: %w", namespace, service, err)
+		}
+
+		return pod.Name, podPort, nil
+	}
+
+	return "", 0, fmt.Errorf("service %s/%s has no ready pods (%d found)", namespace, service, len(pods.Items))
+}
+
+func podReady(pod *corev1.Pod) bool {
+	if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+		return false
+	}
+
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
+
+// resolveTargetPort returns the container port a service's target port refers to in a pod.
+// Target ports can be a number, a container port's name, or left out to mean the same as the service port.
+func resolveTargetPort(pod *corev1.Pod, targetPort intstr.IntOrString, servicePort int32) (int, error) {
+	switch {
+	case targetPort.Type == intstr.Int && targetPort.IntVal == 0:
+		return int(servicePort), nil
+	case targetPort.Type == intstr.Int:
+		return int(targetPort.IntVal), nil
+	}
+
+	var names []string
+	for _, container := range pod.Spec.Containers {
+		for _, p := range container.Ports {
+			if p.Name == targetPort.StrVal {
+				return int(p.ContainerPort), nil
+			}
+			names = append(names, p.Name)
+		}
+	}
+
+	return 0, fmt.Errorf("pod %s has no port named %q (has %s)", pod.Name, targetPort.StrVal, strings.Join(names, ", "))
+}
+
+func poll(ctx context.Context, endpoint string, timeout time.Duration) error {
+	endtime := time.Now().Add(timeout)
+
+	for time.Now().Before(endtime) {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+			time.Sleep(100 * time.Millisecond) // prevent spamming
+
+			resp, err := http.Get(endpoint)
+			if err != nil {
+				continue // assume the server is not yet up.
+			}
+
+			ioutil.ReadAll(resp.Body) // drain body
+			resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				return fmt.Errorf("resp status code: %d", resp.StatusCode)
+			}
+
+			// we're good.
+			return nil
+		}
+	}
+
+	return errors.New("timed out waiting for errands server to start")
+}
@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func testService(selector map[string]string, targetPort intstr.IntOrString) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "errands-api", Namespace: "polygon"},
+		Spec: corev1.ServiceSpec{
+			Selector: selector,
+			Ports:    []corev1.ServicePort{{Port: 80, TargetPort: targetPort}},
+		},
+	}
+}
+
+func testPod(name string, ready bool) *corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "polygon", Labels: map[string]string{"app": "errands"}},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name:  "api",
+			Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 5555}, {Name: "metrics", ContainerPort: 9090}},
+		}}},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+		},
+	}
+}
+
+func TestServicePod(t *testing.T) {
+	selector := map[string]string{"app": "errands"}
+
+	tests := []struct {
+		name    string
+		objects []runtime.Object
+		port    int32
+		pod     string
+		podPort int
+		err     string
+	}{
+		{
+			name:    "named target port",
+			objects: []runtime.Object{testService(selector, intstr.FromString("http")), testPod("booting", false), testPod("ready", true)},
+			port:    80,
+			pod:     "ready",
+			podPort: 5555,
+		},
+		{
+			name:    "numeric target port",
+			objects: []runtime.Object{testService(selector, intstr.FromInt32(8080)), testPod("ready", true)},
+			port:    80,
+			pod:     "ready",
+			podPort: 8080,
+		},
+		{
+			name:    "omitted target port",
+			objects: []runtime.Object{testService(selector, intstr.IntOrString{}), testPod("ready", true)},
+			port:    80,
+			pod:     "ready",
+			podPort: 80,
+		},
+		{
+			name:    "unknown target port name",
+			objects: []runtime.Object{testService(selector, intstr.FromString("grpc")), testPod("ready", true)},
+			port:    80,
+			err:     `service polygon/errands-api: pod ready has no port named "grpc" (has http, metrics)`,
+		},
+		{
+			name: "no service",
+			port: 80,
+			err:  `service "errands-api" not found in namespace "polygon"`,
+		},
+		{
+			name:    "no such service port",
+			objects: []runtime.Object{testService(selector, intstr.FromString("http"))},
+			port:    443,
+			err:     "service polygon/errands-api has no port 443",
+		},
+		{
+			name:    "no selector",
+			objects: []runtime.Object{testService(nil, intstr.FromString("http")), testPod("ready", true)},
+			port:    80,
+			err:     "service polygon/errands-api has no selector",
+		},
+		{
+			name:    "no ready pods",
+			objects: []runtime.Object{testService(selector, intstr.FromString("http")), testPod("unready", false)},
+			port:    80,
+			err:     "service polygon/errands-api has no ready pods (1 found)",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := fake.NewClientset(test.objects...)
+
+			pod, podPort, err := servicePod(context.Background(), client, "polygon", "errands-api", test.port)
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Errorf("expected error containing %q, got %v", test.err, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			if pod != test.pod || podPort != test.podPort {
+				t.Errorf("expected %s:%d, got %s:%d", test.pod, test.podPort, pod, podPort)
+			}
+		})
+	}
+}
+
+func TestResolveTargetPort(t *testing.T) {
+	pod := testPod("ready", true)
+
+	tests := []struct {
+		name       string
+		targetPort intstr.IntOrString
+		want       int
+		err        string
+	}{
+		{name: "named", targetPort: intstr.FromString("metrics"), want: 9090},
+		{name: "numeric", targetPort: intstr.FromInt32(8080), want: 8080},
+		{name: "omitted", targetPort: intstr.IntOrString{}, want: 80},
+		{name: "unknown name", targetPort: intstr.FromString("grpc"), err: `pod ready has no port named "grpc" (has http, metrics)`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			port, err := resolveTargetPort(pod, test.targetPort, 80)
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Errorf("expected error containing %q, got %v", test.err, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			if port != test.want {
+				t.Errorf("expected port %d, got %d", test.want, port)
+			}
+		})
+	}
+}
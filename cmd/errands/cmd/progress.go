@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// progressBarWidth is the number of characters in a progress bar, not counting its brackets and counts.
+const progressBarWidth = 40
+
+// progressBar draws the progress of a bulk action on a terminal. When w isn't a terminal nothing is drawn,
+// so logs and pipes don't fill up with redraws.
+type progressBar struct {
+	w       io.Writer
+	enabled bool
+
+	mu    sync.Mutex
+	total int
+	done  int
+}
+
+func newProgressBar(f *os.File, total int) *progressBar {
+	bar := &progressBar{w: f, enabled: isTerminal(f), total: total}
+	bar.draw()
+
+	return bar
+}
+
+// increment marks one more item as done. It's safe to call from multiple goroutines.
+func (b *progressBar) increment() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.done++
+	b.draw()
+}
+
+// finish ends the line the bar is drawn on, so whatever's written next starts on its own line.
+func (b *progressBar) finish() {
+	if b.enabled {
+		fmt.Fprintln(b.w)
+	}
+}
+
+func (b *progressBar) draw() {
+	if !b.enabled || b.total == 0 {
+		return
+	}
+
+	filled := b.done * progressBarWidth / b.total
+	fmt.Fprintf(b.w, "\r[%s%s] %d/%d", strings.Repeat("=", filled), strings.Repeat(" ", progressBarWidth-filled), b.done, b.total)
+}
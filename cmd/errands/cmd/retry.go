@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/polygon-io/errands-server/schemas"
+	"github.com/spf13/cobra"
+)
+
+func (ec *errandsCmd) newRetryCommand() (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:   "retry",
+		Short: "requeues errands by ID, type, or status",
+		Long: `
+		Retries an errand by --id, or every failed or completed errand of a --type matching the filter flags.
+
+		Bulk retries can't include active or blocked errands, since the server would requeue errands that
+		are still running or waiting on their dependencies. Like bulk deletes, they show how many errands
+		match and a sample of them, then ask for confirmation. Pass --yes to skip the prompt, which is
+		required when stdin isn't a terminal.
+		`,
+		PreRunE: ec.bindViperFlagsPreRun,
+		RunE:    ec.retry,
+	}
+
+	addErrandFilterFlags(cmd, "failed")
+	cmd.Flags().String("id", "", "ID of the errand to retry")
+	cmd.Flags().Bool("dry-run", false, "Don't actually retry anything. Only used for bulk retries")
+	cmd.Flags().BoolP("yes", "y", false, "Don't ask for confirmation before retrying errands in bulk")
+	cmd.Flags().Int("concurrency", 4, "Max concurrent retries")
+
+	return cmd, nil
+}
+
+func (ec *errandsCmd) retry(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p, err := ec.printer()
+	if err != nil {
+		return err
+	}
+
+	filter, err := ec.errandFilter()
+	if err != nil {
+		return err
+	}
+
+	id := ec.viper.GetString("id")
+	if id == "" {
+		if err := checkBulkRetryFilter(filter); err != nil {
+			return err
+		}
+	}
+
+	stopPortForward, err := ec.startPortForward(ctx)
+	if err != nil {
+		return err
+	}
+	defer stopPortForward()
+
+	if id != "" {
+		if _, err := ec.api.RetryErrand(ctx, id); err != nil {
+			return fmt.Errorf("failed to retry errand %s: %w", id, err)
+		}
+
+		fmt.Printf("retried %s\n", id)
+		return nil
+	}
+
+	jobs, err := filter.list(ctx, ec.api)
+	if err != nil {
+		return fmt.Errorf("failed to get errands: %w", err)
+	}
+
+	if ec.viper.GetBool("dry-run") {
+		fmt.Fprintf(os.Stderr, "(dry-run) %d errands would be retried\n", len(jobs))
+		return p.printList(os.Stdout, errandItems(jobs), errandColumns)
+	}
+
+	if len(jobs) == 0 {
+		fmt.Fprintln(os.Stderr, "no errands match")
+		return nil
+	}
+
+	if !ec.viper.GetBool("yes") {
+		confirmed, err := confirmBulk(jobs, "retry", "retried")
+		if err != nil {
+			return err
+		}
+
+		if !confirmed {
+			fmt.Fprintln(os.Stderr, "aborted; nothing was retried")
+			return nil
+		}
+	}
+
+	result := forEachErrand(ctx, jobs, ec.viper.GetInt("concurrency"), func(ctx context.Context, job schemas.Errand) error {
+		_, err := ec.api.RetryErrand(ctx, job.ID)
+		return err
+	})
+
+	for id, err := range result.failed {
+		fmt.Printf("failed to retry errand %s: %s\n", id, err)
+	}
+	fmt.Printf("retried %d of %d errands, %d failed\n", result.succeeded, len(jobs), len(result.failed))
+
+	if len(result.failed) > 0 {
+		return fmt.Errorf("failed to retry %d errands", len(result.failed))
+	}
+
+	return nil
+}
+
+// checkBulkRetryFilter makes sure a bulk retry is limited to a single type and to errands that have finished.
+// The server requeues any errand that isn't inactive, including active and blocked ones.
+func checkBulkRetryFilter(filter *errandFilter) error {
+	if filter.errandType == "" {
+		return errors.New("--type is required to retry errands in bulk")
+	}
+
+	if len(filter.statuses) == 0 {
+		return errors.New("--status is required to retry errands in bulk; use failed, completed or both")
+	}
+
+	for status := range filter.statuses {
+		if status != schemas.StatusFailed && status != schemas.StatusCompleted {
+			return fmt.Errorf("can't retry %s errands in bulk; --status can only be failed or completed", status)
+		}
+	}
+
+	return nil
+}
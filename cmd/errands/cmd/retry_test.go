@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRetryFlagErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		err  string
+	}{
+		{
+			name: "no type",
+			args: []string{"--status=failed"},
+			err:  "--type is required to retry errands in bulk",
+		},
+		{
+			name: "active errands",
+			args: []string{"--type=sort", "--status=failed,active"},
+			err:  "can't retry active errands in bulk",
+		},
+		{
+			name: "blocked errands",
+			args: []string{"--type=sort", "--status=blocked"},
+			err:  "can't retry blocked errands in bulk",
+		},
+		{
+			name: "every status",
+			args: []string{"--type=sort", "--status="},
+			err:  "--status is required to retry errands in bulk",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := runErrands(t, "", append([]string{"retry", "--bootstrap=false"}, test.args...)...)
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("expected error containing %q, got %v", test.err, err)
+			}
+		})
+	}
+}
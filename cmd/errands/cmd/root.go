@@ -1,17 +1,9 @@
 package cmd
 
 import (
-	"context"
-	"errors"
 	"fmt"
-	"io"
-	"io/ioutil"
 	"net"
-	"net/http"
-	"os"
-	"os/exec"
 	"strings"
-	"time"
 
 	errandz "github.com/polygon-io/errands-go"
 	"github.com/spf13/cobra"
@@ -20,12 +12,17 @@ import (
 
 const (
 	defaultErrandsPort = 5555
+	defaultNamespace   = "polygon"
+	defaultService     = "errands-api"
 )
 
 type errandsCmd struct {
 	viper    *viper.Viper
 	api      *errandz.ErrandsAPI
 	endpoint string
+
+	// configLoaded is set once the config file and profile have been read, since pre-run hooks run more than once.
+	configLoaded bool
 }
 
 func NewCommand() (*cobra.Command, error) {
@@ -38,15 +35,21 @@ func NewCommand() (*cobra.Command, error) {
 		Short: "provides a CLI for interacting with the errands service",
 		Long: `
 		errands is a CLI for interacting with the errands service. It provides
-		several subcommands for errand actions like list and delete. It can
+		several subcommands for errand actions like list, get and delete. It can
 		also port-forward the errands server automatically so you don't have to.
 		`,
 		PersistentPreRunE: ec.rootPersistentPreRun,
 	}
 
+	cmd.PersistentFlags().String("profile", "", "config file profile to use; see errands config --help")
 	cmd.PersistentFlags().Bool("bootstrap", true, "port-forward the errands server")
 	cmd.PersistentFlags().Int("port", defaultErrandsPort, "port for the errands server")
 	cmd.PersistentFlags().String("endpoint", "", "if you need to connect to an endpoint other than localhost then use this flag")
+	cmd.PersistentFlags().String("kubeconfig", "", "path to the kubeconfig file used to port-forward; defaults to $KUBECONFIG or ~/.kube/config")
+	cmd.PersistentFlags().String("kube-context", "", "kubeconfig context used to port-forward; defaults to the current context")
+	cmd.PersistentFlags().String("namespace", defaultNamespace, "namespace of the errands service to port-forward")
+	cmd.PersistentFlags().String("service", defaultService, "name of the errands service to port-forward")
+	cmd.PersistentFlags().StringP("output", "o", "table", "output format: table, wide, json, jsonl, yaml, csv or go-template=<template>")
 
 	list, err := ec.newListCommand()
 	if err != nil {
@@ -58,8 +61,50 @@ func NewCommand() (*cobra.Command, error) {
 		return nil, fmt.Errorf("create delete command: %w", err)
 	}
 
+	create, err := ec.newCreateCommand()
+	if err != nil {
+		return nil, fmt.Errorf("create create command: %w", err)
+	}
+
+	retry, err := ec.newRetryCommand()
+	if err != nil {
+		return nil, fmt.Errorf("create retry command: %w", err)
+	}
+
+	watch, err := ec.newWatchCommand()
+	if err != nil {
+		return nil, fmt.Errorf("create watch command: %w", err)
+	}
+
+	get, err := ec.newGetCommand()
+	if err != nil {
+		return nil, fmt.Errorf("create get command: %w", err)
+	}
+
+	stats, err := ec.newStatsCommand()
+	if err != nil {
+		return nil, fmt.Errorf("create stats command: %w", err)
+	}
+
+	config, err := ec.newConfigCommand()
+	if err != nil {
+		return nil, fmt.Errorf("create config command: %w", err)
+	}
+
+	pipeline, err := ec.newPipelineCommand()
+	if err != nil {
+		return nil, fmt.Errorf("create pipeline command: %w", err)
+	}
+
 	cmd.AddCommand(list)
 	cmd.AddCommand(delete)
+	cmd.AddCommand(get)
+	cmd.AddCommand(create)
+	cmd.AddCommand(retry)
+	cmd.AddCommand(watch)
+	cmd.AddCommand(stats)
+	cmd.AddCommand(pipeline)
+	cmd.AddCommand(config)
 
 	ec.viper.SetEnvPrefix("POLY_ERRANDS")
 	ec.viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_")) // Make sure env vars use underscore instead of dash
@@ -82,6 +127,10 @@ func (ec *errandsCmd) bindViperFlagsPreRun(cmd *cobra.Command, _ []string) error
 		return fmt.Errorf("bind pflags: %w", err)
 	}
 
+	if err := ec.loadConfig(); err != nil {
+		return err
+	}
+
 	// if bootstrapping then we need to check if the port is available.
 	if ec.viper.GetBool("bootstrap") {
 		if ec.viper.GetString("endpoint") != "" {
@@ -107,6 +156,10 @@ func (ec *errandsCmd) bindViperFlagsPreRun(cmd *cobra.Command, _ []string) error
 	ec.endpoint = endpoint
 	ec.api = errandz.New(endpoint)
 
+	for key, value := range ec.viper.GetStringMapString("headers") {
+		ec.api.SetHeader(key, value)
+	}
+
 	return nil
 }
 
@@ -117,63 +170,3 @@ func (e *errandsCmd) rootPersistentPreRun(cmd *cobra.Command, args []string) err
 
 	return nil
 }
-
-func (ec *errandsCmd) portForwardErrandsServer(ctx context.Context) (*exec.Cmd, error) {
-	port := ec.viper.GetInt("port")
-	if port == 0 {
-		return nil, fmt.Errorf("port is required")
-	}
-
-	cmd := createKubeCmdContext(ctx, "polygon", "port-forward", "svc/errands-api", fmt.Sprintf("%d:80", port))
-	cmd.Stdout = io.Discard
-	if err := cmd.Start(); err != nil {
-		return nil, fmt.Errorf("start port-forward: %w", err)
-	}
-
-	// using the metrics endpoint to check if the server is up
-	endpoint := fmt.Sprintf("%s/metrics", ec.endpoint)
-	return cmd, poll(ctx, endpoint, 5*time.Second)
-}
-
-func poll(ctx context.Context, endpoint string, timeout time.Duration) error {
-	endtime := time.Now().Add(timeout)
-
-	for time.Now().Before(endtime) {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-			time.Sleep(100 * time.Millisecond) // prevent spamming
-
-			resp, err := http.Get(endpoint)
-			if err != nil {
-				continue // assume the server is not yet up.
-			}
-
-			ioutil.ReadAll(resp.Body) // drain body
-			resp.Body.Close()
-
-			if resp.StatusCode != http.StatusOK {
-				return fmt.Errorf("resp status code: %d", resp.StatusCode)
-			}
-
-			// we're good.
-			return nil
-		}
-	}
-
-	return errors.New("timed out waiting for errands server to start")
-}
-
-func createKubeCmdContext(ctx context.Context, namespace, subCommand string, args ...string) *exec.Cmd {
-	cmd := exec.CommandContext(ctx, "kubectl", subCommand)
-	if namespace != "" {
-		cmd.Args = append(cmd.Args, "--namespace", namespace)
-	}
-
-	cmd.Args = append(cmd.Args, args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-
-	return cmd
-}
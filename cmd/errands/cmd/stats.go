@@ -0,0 +1,273 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	errandz "github.com/polygon-io/errands-go"
+	"github.com/polygon-io/errands-server/schemas"
+	"github.com/spf13/cobra"
+)
+
+// topFailureReasons is the number of most common failure reasons shown for each type.
+const topFailureReasons = 3
+
+func (ec *errandsCmd) newStatsCommand() (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "summarizes the health of the errand queues by type",
+		Long: `
+		Shows, for each errand type and overall: counts by status, the age of the oldest inactive and
+		active errands, the average and 95th percentile processing time of completed errands, the failure
+		rate of finished errands and the most common failure reasons.
+
+		Errands deleted on completion aren't counted, since the server no longer has them.
+		`,
+		PreRunE: ec.bindViperFlagsPreRun,
+		RunE:    ec.stats,
+	}
+
+	cmd.Flags().String("type", "", "only show stats for this errand type")
+
+	return cmd, nil
+}
+
+func (ec *errandsCmd) stats(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p, err := ec.printer()
+	if err != nil {
+		return err
+	}
+
+	stopPortForward, err := ec.startPortForward(ctx)
+	if err != nil {
+		return err
+	}
+	defer stopPortForward()
+
+	var jobs *errandz.ErrandsResponse
+	if errandType := ec.viper.GetString("type"); errandType != "" {
+		jobs, err = ec.api.ListErrandsContext(ctx, "type", errandType)
+	} else {
+		jobs, err = ec.api.GetErrandsContext(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("get errands: %w", err)
+	}
+
+	stats := computeTypeStats(jobs.Results, time.Now())
+
+	items := make([]outputItem, len(stats))
+	for i, s := range stats {
+		items[i] = s
+	}
+
+	return p.printList(os.Stdout, items, typeStatsColumns)
+}
+
+// typeStats summarizes the errands of a type, or of every type if errandType is empty.
+type typeStats struct {
+	errandType string
+	total      int
+	counts     map[schemas.Status]int
+
+	// oldestInactive and oldestActive are the ages of the oldest inactive and active errands, or zero if there are none.
+	oldestInactive time.Duration
+	oldestActive   time.Duration
+
+	avgProcessing time.Duration
+	p95Processing time.Duration
+
+	// failureRate is the fraction of finished errands that failed.
+	failureRate    float64
+	failureReasons []failureReason
+
+	processing []time.Duration
+	reasons    map[string]int
+}
+
+type failureReason struct {
+	Reason string `json:"reason"`
+	Count  int    `json:"count"`
+}
+
+// MarshalJSON encodes the stats with durations in milliseconds, like the server's timestamps.
+func (s *typeStats) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type                 string                 `json:"type"`
+		Total                int                    `json:"total"`
+		Counts               map[schemas.Status]int `json:"counts"`
+		OldestInactiveMillis int64                  `json:"oldestInactiveMillis"`
+		OldestActiveMillis   int64                  `json:"oldestActiveMillis"`
+		AvgProcessingMillis  int64                  `json:"avgProcessingMillis"`
+		P95ProcessingMillis  int64                  `json:"p95ProcessingMillis"`
+		FailureRate          float64                `json:"failureRate"`
+		FailureReasons       []failureReason        `json:"failureReasons"`
+	}{
+		Type:                 s.errandType,
+		Total:                s.total,
+		Counts:               s.counts,
+		OldestInactiveMillis: s.oldestInactive.Milliseconds(),
+		OldestActiveMillis:   s.oldestActive.Milliseconds(),
+		AvgProcessingMillis:  s.avgProcessing.Milliseconds(),
+		P95ProcessingMillis:  s.p95Processing.Milliseconds(),
+		FailureRate:          s.failureRate,
+		FailureReasons:       s.failureReasons,
+	})
+}
+
+// computeTypeStats computes stats for each type of errand, sorted by type, followed by stats for all of them.
+func computeTypeStats(jobs []schemas.Errand, now time.Time) []*typeStats {
+	byType := make(map[string]*typeStats)
+	all := newTypeStats("")
+
+	for i := range jobs {
+		job := &jobs[i]
+
+		s, exists := byType[job.Type]
+		if !exists {
+			s = newTypeStats(job.Type)
+			byType[job.Type] = s
+		}
+
+		s.add(job, now)
+		all.add(job, now)
+	}
+
+	stats := make([]*typeStats, 0, len(byType)+1)
+	for _, s := range byType {
+		stats = append(stats, s)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].errandType < stats[j].errandType })
+
+	if len(byType) != 1 {
+		stats = append(stats, all)
+	}
+
+	for _, s := range stats {
+		s.finish()
+	}
+
+	return stats
+}
+
+func newTypeStats(errandType string) *typeStats {
+	return &typeStats{
+		errandType: errandType,
+		counts:     make(map[schemas.Status]int),
+		reasons:    make(map[string]int),
+	}
+}
+
+func (s *typeStats) add(job *schemas.Errand, now time.Time) {
+	s.total++
+	s.counts[job.Status]++
+
+	switch job.Status {
+	case schemas.StatusInactive:
+		if age := now.Sub(time.UnixMilli(job.Created)); age > s.oldestInactive {
+			s.oldestInactive = age
+		}
+	case schemas.StatusActive:
+		if age := now.Sub(time.UnixMilli(job.Started)); job.Started != 0 && age > s.oldestActive {
+			s.oldestActive = age
+		}
+	case schemas.StatusCompleted:
+		if job.Started != 0 && job.Completed >= job.Started {
+			s.processing = append(s.processing, time.Duration(job.Completed-job.Started)*time.Millisecond)
+		}
+	case schemas.StatusFailed:
+		if reason := errandz.FailureReason(job); reason != "" {
+			s.reasons[reason]++
+		}
+	}
+}
+
+// finish works out the stats that need every errand to have been added.
+func (s *typeStats) finish() {
+	if len(s.processing) > 0 {
+		sort.Slice(s.processing, func(i, j int) bool { return s.processing[i] < s.processing[j] })
+
+		var sum time.Duration
+		for _, d := range s.processing {
+			sum += d
+		}
+
+		s.avgProcessing = sum / time.Duration(len(s.processing))
+		s.p95Processing = s.processing[(len(s.processing)*95+99)/100-1]
+	}
+
+	if finished := s.counts[schemas.StatusCompleted] + s.counts[schemas.StatusFailed]; finished > 0 {
+		s.failureRate = float64(s.counts[schemas.StatusFailed]) / float64(finished)
+	}
+
+	for reason, count := range s.reasons {
+		s.failureReasons = append(s.failureReasons, failureReason{Reason: reason, Count: count})
+	}
+	sort.Slice(s.failureReasons, func(i, j int) bool {
+		if s.failureReasons[i].Count != s.failureReasons[j].Count {
+			return s.failureReasons[i].Count > s.failureReasons[j].Count
+		}
+		return s.failureReasons[i].Reason < s.failureReasons[j].Reason
+	})
+	if len(s.failureReasons) > topFailureReasons {
+		s.failureReasons = s.failureReasons[:topFailureReasons]
+	}
+}
+
+// typeStatsColumns are the columns stats are shown with in table, wide and csv output.
+var typeStatsColumns = []column{
+	{header: "TYPE", value: func(item outputItem) string {
+		if t := item.(*typeStats).errandType; t != "" {
+			return t
+		}
+		return "(all)"
+	}},
+	{header: "TOTAL", value: func(item outputItem) string { return strconv.Itoa(item.(*typeStats).total) }},
+	{header: "BLOCKED", wide: true, value: statusCount(schemas.StatusBlocked)},
+	{header: "INACTIVE", value: statusCount(schemas.StatusInactive)},
+	{header: "ACTIVE", value: statusCount(schemas.StatusActive)},
+	{header: "FAILED", value: statusCount(schemas.StatusFailed)},
+	{header: "COMPLETED", value: statusCount(schemas.StatusCompleted)},
+	{header: "OLDEST INACTIVE", value: func(item outputItem) string { return formatAge(item.(*typeStats).oldestInactive) }},
+	{header: "OLDEST ACTIVE", wide: true, value: func(item outputItem) string { return formatAge(item.(*typeStats).oldestActive) }},
+	{header: "AVG TIME", value: func(item outputItem) string { return formatAge(item.(*typeStats).avgProcessing) }},
+	{header: "P95 TIME", value: func(item outputItem) string { return formatAge(item.(*typeStats).p95Processing) }},
+	{header: "FAILURE RATE", value: func(item outputItem) string {
+		return strconv.FormatFloat(item.(*typeStats).failureRate*100, 'f', 1, 64) + "%"
+	}},
+	{header: "TOP FAILURE REASONS", wide: true, value: func(item outputItem) string {
+		var reasons []string
+		for _, r := range item.(*typeStats).failureReasons {
+			reasons = append(reasons, fmt.Sprintf("%s (%d)", r.Reason, r.Count))
+		}
+		return strings.Join(reasons, "; ")
+	}},
+}
+
+func statusCount(status schemas.Status) func(item outputItem) string {
+	return func(item outputItem) string {
+		return strconv.Itoa(item.(*typeStats).counts[status])
+	}
+}
+
+// formatAge formats a duration for a table, leaving it empty if it's zero.
+func formatAge(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+
+	return d.Round(time.Second).String()
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/polygon-io/errands-server/schemas"
+)
+
+func TestComputeTypeStats(t *testing.T) {
+	// Errand timestamps are in milliseconds, so ages only come out exact if now is too.
+	now := time.UnixMilli(time.Now().UnixMilli())
+	ago := func(d time.Duration) int64 { return now.Add(-d).UnixMilli() }
+	failed := func(errandType, reason string) schemas.Errand {
+		return schemas.Errand{Type: errandType, Status: schemas.StatusFailed, Logs: []schemas.Log{
+			{Severity: "INFO", Message: "started"},
+			{Severity: "ERROR", Message: reason},
+		}}
+	}
+
+	jobs := []schemas.Errand{
+		{Type: "sort", Status: schemas.StatusInactive, Created: ago(time.Hour)},
+		{Type: "sort", Status: schemas.StatusInactive, Created: ago(3 * time.Hour)},
+		{Type: "sort", Status: schemas.StatusActive, Started: ago(10 * time.Minute)},
+		// Active errands that haven't got a start time don't count towards the oldest active age.
+		{Type: "sort", Status: schemas.StatusActive},
+		{Type: "sort", Status: schemas.StatusCompleted, Started: ago(time.Hour), Completed: ago(time.Hour - 2*time.Second)},
+		{Type: "sort", Status: schemas.StatusCompleted, Started: ago(time.Hour), Completed: ago(time.Hour - 4*time.Second)},
+		failed("sort", "timeout"),
+		failed("sort", "timeout"),
+		failed("sort", "timeout"),
+		failed("sort", "bad input"),
+		failed("sort", "bad input"),
+		failed("sort", "oom"),
+		failed("sort", "disk full"),
+		{Type: "index", Status: schemas.StatusBlocked},
+	}
+
+	stats := computeTypeStats(jobs, now)
+
+	var types []string
+	for _, s := range stats {
+		types = append(types, s.errandType)
+	}
+	if want := []string{"index", "sort", ""}; !reflect.DeepEqual(types, want) {
+		t.Fatalf("expected stats for %q, got %q", want, types)
+	}
+
+	sorting := stats[1]
+	if sorting.total != 13 || sorting.counts[schemas.StatusFailed] != 7 || sorting.counts[schemas.StatusInactive] != 2 {
+		t.Errorf("unexpected counts: total %d, %v", sorting.total, sorting.counts)
+	}
+	if sorting.oldestInactive != 3*time.Hour {
+		t.Errorf("expected the oldest inactive errand to be 3h old, got %s", sorting.oldestInactive)
+	}
+	if sorting.oldestActive != 10*time.Minute {
+		t.Errorf("expected the oldest active errand to be 10m old, got %s", sorting.oldestActive)
+	}
+	if sorting.avgProcessing != 3*time.Second || sorting.p95Processing != 4*time.Second {
+		t.Errorf("expected 3s average and 4s p95 processing time, got %s and %s", sorting.avgProcessing, sorting.p95Processing)
+	}
+	if sorting.failureRate != 7.0/9.0 {
+		t.Errorf("expected a failure rate of 7/9, got %f", sorting.failureRate)
+	}
+
+	// Ties are broken by reason, and only the top reasons are kept.
+	wantReasons := []failureReason{{Reason: "timeout", Count: 3}, {Reason: "bad input", Count: 2}, {Reason: "disk full", Count: 1}}
+	if !reflect.DeepEqual(sorting.failureReasons, wantReasons) {
+		t.Errorf("expected failure reasons %v, got %v", wantReasons, sorting.failureReasons)
+	}
+
+	index := stats[0]
+	if index.total != 1 || index.failureRate != 0 || index.failureReasons != nil || index.p95Processing != 0 {
+		t.Errorf("expected an index errand with no finished errands, got %+v", index)
+	}
+
+	all := stats[2]
+	if all.total != len(jobs) || all.counts[schemas.StatusBlocked] != 1 || all.failureRate != sorting.failureRate {
+		t.Errorf("expected the (all) row to cover every errand, got %+v", all)
+	}
+}
+
+func TestComputeTypeStatsRows(t *testing.T) {
+	tests := []struct {
+		name  string
+		jobs  []schemas.Errand
+		types []string
+	}{
+		{name: "no errands", types: []string{""}},
+		{name: "one type", jobs: []schemas.Errand{{Type: "a"}, {Type: "a"}}, types: []string{"a"}},
+		{name: "several types", jobs: []schemas.Errand{{Type: "b"}, {Type: "a"}}, types: []string{"a", "b", ""}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var types []string
+			for _, s := range computeTypeStats(test.jobs, time.Now()) {
+				types = append(types, s.errandType)
+			}
+
+			if !reflect.DeepEqual(types, test.types) {
+				t.Errorf("expected stats for %q, got %q", test.types, types)
+			}
+		})
+	}
+}
+
+func TestP95Processing(t *testing.T) {
+	// The 95th percentile is the smallest processing time at least 95% of errands took no longer than.
+	tests := []struct {
+		n    int
+		want time.Duration
+	}{
+		{n: 1, want: 1 * time.Second},
+		{n: 2, want: 2 * time.Second},
+		{n: 19, want: 19 * time.Second},
+		{n: 20, want: 19 * time.Second},
+		{n: 21, want: 20 * time.Second},
+		{n: 100, want: 95 * time.Second},
+		{n: 101, want: 96 * time.Second},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprint(test.n), func(t *testing.T) {
+			// Added in reverse, so the processing times have to be sorted.
+			var jobs []schemas.Errand
+			for i := test.n; i > 0; i-- {
+				jobs = append(jobs, schemas.Errand{Type: "a", Status: schemas.StatusCompleted, Started: 1, Completed: 1 + int64(i)*1000})
+			}
+
+			stats := computeTypeStats(jobs, time.Now())
+			if got := stats[0].p95Processing; got != test.want {
+				t.Errorf("expected p95 of %s, got %s", test.want, got)
+			}
+		})
+	}
+}
+
+func TestFormatAge(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{d: 0, want: ""},
+		{d: 1500 * time.Microsecond, want: "2ms"},
+		{d: 1400 * time.Millisecond, want: "1s"},
+		{d: 90*time.Minute + 700*time.Millisecond, want: "1h30m1s"},
+	}
+
+	for _, test := range tests {
+		if got := formatAge(test.d); got != test.want {
+			t.Errorf("formatAge(%s): expected %q, got %q", test.d, test.want, got)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	errandz "github.com/polygon-io/errands-go"
+	"github.com/polygon-io/errands-server/schemas"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// throughputWindow is how far back completions are counted to work out throughput.
+	throughputWindow = 5 * time.Minute
+
+	// recentFailures is the number of most recent failures watch shows.
+	recentFailures = 5
+)
+
+func (ec *errandsCmd) newWatchCommand() (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "shows a live view of the errands of a type",
+		Long: `
+		Refreshes counts by status, throughput, the age of the oldest inactive errand and recent failures
+		for the errands of a type. When the output isn't a terminal, a line is printed for each refresh instead.
+
+		Throughput is measured from completion times, so errands deleted on completion aren't counted.
+		`,
+		PreRunE: ec.bindViperFlagsPreRun,
+		RunE:    ec.watch,
+	}
+
+	cmd.Flags().String("type", "", "errand type to watch")
+	cmd.Flags().Duration("interval", 2*time.Second, "how often to refresh")
+
+	return cmd, nil
+}
+
+func (ec *errandsCmd) watch(cmd *cobra.Command, args []string) error {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	errandType := ec.viper.GetString("type")
+	if errandType == "" {
+		return errors.New("--type is required")
+	}
+
+	interval := ec.viper.GetDuration("interval")
+	if interval <= 0 {
+		return errors.New("--interval must be positive")
+	}
+
+	stopPortForward, err := ec.startPortForward(ctx)
+	if err != nil {
+		return err
+	}
+	defer stopPortForward()
+
+	tty := isTerminal(os.Stdout)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		jobs, err := ec.api.ListErrandsContext(ctx, "type", errandType)
+		now := time.Now()
+
+		switch {
+		case err != nil && tty:
+			fmt.Printf("\033[H\033[2J%s  error listing errands: %s\n", now.Format(time.Kitchen), err)
+		case err != nil:
+			fmt.Printf("%s error listing errands: %s\n", now.Format(time.RFC3339), err)
+		case tty:
+			fmt.Print("\033[H\033[2J")
+			summarizeTopic(errandType, jobs.Results, now).writeDetails(os.Stdout)
+		default:
+			fmt.Println(summarizeTopic(errandType, jobs.Results, now).line())
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// topicSummary is a snapshot of the errands of a type.
+type topicSummary struct {
+	errandType string
+	at         time.Time
+
+	counts map[schemas.Status]int
+	total  int
+
+	// perMinute is the average number of errands completed per minute over the throughput window.
+	perMinute float64
+
+	// oldestInactive is the age of the oldest errand waiting to be processed, or zero if there are none.
+	oldestInactive time.Duration
+
+	failures []schemas.Errand
+}
+
+func summarizeTopic(errandType string, jobs []schemas.Errand, now time.Time) *topicSummary {
+	summary := &topicSummary{
+		errandType: errandType,
+		at:         now,
+		counts:     make(map[schemas.Status]int),
+		total:      len(jobs),
+	}
+
+	windowStart := now.Add(-throughputWindow)
+	var completed int
+	for _, job := range jobs {
+		summary.counts[job.Status]++
+
+		switch job.Status {
+		case schemas.StatusCompleted:
+			if time.UnixMilli(job.Completed).After(windowStart) {
+				completed++
+			}
+		case schemas.StatusInactive:
+			if age := now.Sub(time.UnixMilli(job.Created)); age > summary.oldestInactive {
+				summary.oldestInactive = age
+			}
+		case schemas.StatusFailed:
+			summary.failures = append(summary.failures, job)
+		}
+	}
+
+	summary.perMinute = float64(completed) / throughputWindow.Minutes()
+
+	sort.Slice(summary.failures, func(i, j int) bool {
+		return summary.failures[i].Failed > summary.failures[j].Failed
+	})
+	if len(summary.failures) > recentFailures {
+		summary.failures = summary.failures[:recentFailures]
+	}
+
+	return summary
+}
+
+// line formats the summary on a single line, for output that isn't a terminal.
+func (s *topicSummary) line() string {
+	parts := []string{s.at.Format(time.RFC3339), "type=" + s.errandType, fmt.Sprintf("total=%d", s.total)}
+	for _, status := range schemas.ErrandStatuses {
+		parts = append(parts, fmt.Sprintf("%s=%d", status, s.counts[status]))
+	}
+	parts = append(parts,
+		fmt.Sprintf("completed_per_min=%.1f", s.perMinute),
+		fmt.Sprintf("oldest_inactive=%s", s.oldestInactive.Round(time.Second)),
+	)
+
+	return strings.Join(parts, " ")
+}
+
+// writeDetails writes the summary as a small dashboard, for output that is a terminal.
+func (s *topicSummary) writeDetails(w io.Writer) {
+	fmt.Fprintf(w, "errands of type %s at %s (Ctrl-C to stop)\n\n", s.errandType, s.at.Format(time.Kitchen))
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, status := range schemas.ErrandStatuses {
+		fmt.Fprintf(tw, "%s\t%d\n", status, s.counts[status])
+	}
+	fmt.Fprintf(tw, "total\t%d\n", s.total)
+	tw.Flush()
+
+	fmt.Fprintf(w, "\ncompleted per minute (last %s): %.1f\n", throughputWindow, s.perMinute)
+	if s.oldestInactive > 0 {
+		fmt.Fprintf(w, "oldest inactive errand: %s old\n", s.oldestInactive.Round(time.Second))
+	}
+
+	if len(s.failures) > 0 {
+		fmt.Fprintln(w, "\nrecent failures:")
+		tw = tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		for _, job := range s.failures {
+			fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\n", time.UnixMilli(job.Failed).Format(time.Stamp), job.ID, job.Name, errandz.FailureReason(&job))
+		}
+		tw.Flush()
+	}
+}
+
+// isTerminal reports whether f is a terminal rather than a file or pipe.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,38 @@
+package errands
+
+import (
+	"context"
+	"sync"
+)
+
+// forEach calls fn for each index in [0, n) with at most concurrency calls running at once, or one at a time
+// if concurrency is less than 1. It stops starting new calls once ctx is done, and waits for running calls to return,
+// so fn isn't called for the indexes after that.
+func forEach(ctx context.Context, n, concurrency int, fn func(i int)) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+
+	for i := 0; i < n; i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			fn(i)
+		}(i)
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,76 @@
+package errands
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestForEach(t *testing.T) {
+	tests := []struct {
+		name        string
+		n           int
+		concurrency int
+	}{
+		{name: "concurrent", n: 20, concurrency: 3},
+		{name: "more workers than items", n: 2, concurrency: 5},
+		{name: "no concurrency given", n: 5, concurrency: 0},
+		{name: "no items", n: 0, concurrency: 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				mu             sync.Mutex
+				running, peak  int
+				called         = make([]bool, test.n)
+				maxConcurrency = test.concurrency
+			)
+			if maxConcurrency < 1 {
+				maxConcurrency = 1
+			}
+
+			forEach(context.Background(), test.n, test.concurrency, func(i int) {
+				mu.Lock()
+				running++
+				if running > peak {
+					peak = running
+				}
+				called[i] = true
+				mu.Unlock()
+
+				time.Sleep(time.Millisecond)
+
+				mu.Lock()
+				running--
+				mu.Unlock()
+			})
+
+			for i, ok := range called {
+				if !ok {
+					t.Errorf("fn wasn't called for %d", i)
+				}
+			}
+			if peak > maxConcurrency {
+				t.Errorf("expected at most %d calls at once, got %d", maxConcurrency, peak)
+			}
+		})
+	}
+}
+
+func TestForEachStopsWhenCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var calls int
+	forEach(ctx, 10, 1, func(i int) {
+		calls++
+		if i == 2 {
+			cancel()
+		}
+	})
+
+	if calls != 3 {
+		t.Errorf("expected forEach to stop after the call that canceled ctx, got %d calls", calls)
+	}
+}
@@ -2,6 +2,8 @@ package errands
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -12,6 +14,13 @@ import (
 type ErrandsAPI struct {
 	EndpointURL string
 	Processors  []*Processor
+
+	// Outbox, if set, durably records errand outcomes before processors report them. See EnableOutbox.
+	Outbox *Outbox
+
+	rateLimits rateLimiters
+	breaker    *circuitBreaker
+	headers    http.Header
 }
 
 func New(url string) *ErrandsAPI {
@@ -20,6 +29,15 @@ func New(url string) *ErrandsAPI {
 	return obj
 }
 
+// SetHeader adds a header to every request sent to the errands server, like an Authorization header for a server
+// behind an authenticating proxy. It isn't safe to call while requests are being made.
+func (e *ErrandsAPI) SetHeader(key, value string) {
+	if e.headers == nil {
+		e.headers = make(http.Header)
+	}
+	e.headers.Set(key, value)
+}
+
 //easyjson:json
 type ErrandsResponse struct {
 	Results []schemas.Errand `json:"results"`
@@ -32,34 +50,88 @@ type ErrandResponse struct {
 	Status  string         `json:"status"`
 }
 
+// GetErrands lists every errand. It's the same as GetErrandsContext with a background context.
 func (e *ErrandsAPI) GetErrands() (*ErrandsResponse, error) {
-	res, err := e.get("/v1/errands/")
+	return e.GetErrandsContext(context.Background())
+}
+
+// GetErrandsContext lists every errand. The context can cancel the request, including while it waits for the rate limiter.
+func (e *ErrandsAPI) GetErrandsContext(ctx context.Context) (*ErrandsResponse, error) {
+	res, err := e.get(ctx, EndpointList, "/v1/errands/")
 	if err != nil {
 		return &ErrandsResponse{}, err
 	}
 	return parseErrandsResponse(res)
 }
 
+// ErrErrandNotFound is returned by GetErrand when the server has no errand with the given ID.
+var ErrErrandNotFound = errors.New("errand not found")
+
+// GetErrand fetches a single errand by ID. The server has no route for getting a single errand
+// (GET /v1/errand/:id is handled as a create), so this lists every errand and picks it out,
+// which gets slower the more errands the server holds.
+// If the server has no errand with the ID, the error wraps ErrErrandNotFound.
+func (e *ErrandsAPI) GetErrand(ctx context.Context, errandID string) (*ErrandResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, e.EndpointURL+"/v1/errands/", nil)
+	if err != nil {
+		return nil, fmt.Errorf("new http request: %w", err)
+	}
+
+	errands := &ErrandsResponse{}
+	if err := e.requestAndUnmarshalResponse(EndpointList, req, errands); err != nil {
+		return nil, err
+	}
+
+	if errands.Status != "OK" {
+		return nil, fmt.Errorf("get errand %s: response status %q", errandID, errands.Status)
+	}
+
+	for i := range errands.Results {
+		if errands.Results[i].ID == errandID {
+			return &ErrandResponse{Results: errands.Results[i], Status: errands.Status}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("get errand %s: %w", errandID, ErrErrandNotFound)
+}
+
 // ListErrands queries the errands API for a list of errands that match the given query.
 // Possible options for key are: status and type.
 func (e *ErrandsAPI) ListErrands(key, val string) (*ErrandsResponse, error) {
+	return e.ListErrandsContext(context.Background(), key, val)
+}
+
+// ListErrandsContext is ListErrands with a context that can cancel the request.
+func (e *ErrandsAPI) ListErrandsContext(ctx context.Context, key, val string) (*ErrandsResponse, error) {
 	path := fmt.Sprintf("/v1/errands/list/%s/%s", key, val)
-	res, err := e.get(path)
+	res, err := e.get(ctx, EndpointList, path)
 	if err != nil {
 		return &ErrandsResponse{}, err
 	}
 	return parseErrandsResponse(res)
 }
 
+// CreateErrand creates an errand. It's the same as CreateErrandContext with a background context.
 func (e *ErrandsAPI) CreateErrand(errand *schemas.Errand) (*ErrandResponse, error) {
+	return e.CreateErrandContext(context.Background(), errand)
+}
+
+// CreateErrandContext creates an errand. The context can cancel the request, including while it waits for the rate limiter.
+func (e *ErrandsAPI) CreateErrandContext(ctx context.Context, errand *schemas.Errand) (*ErrandResponse, error) {
 	errandBytes, err := errand.MarshalJSON()
 	if err != nil {
 		return &ErrandResponse{}, err
 	}
-	resp, err := http.Post(e.EndpointURL+"/v1/errands/", "application/json", bytes.NewBuffer(errandBytes))
+	req, err := http.NewRequestWithContext(ctx, "POST", e.EndpointURL+"/v1/errands/", bytes.NewBuffer(errandBytes))
+	if err != nil {
+		return &ErrandResponse{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := e.do(EndpointCreate, req)
 	if err != nil {
 		return &ErrandResponse{}, err
 	}
+	defer resp.Body.Close()
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		return &ErrandResponse{}, err
@@ -67,13 +139,20 @@ func (e *ErrandsAPI) CreateErrand(errand *schemas.Errand) (*ErrandResponse, erro
 	return parseErrandResponse(body)
 }
 
+// RequestErrandToProcess claims the next inactive errand of a type. It's the same as RequestErrandToProcessContext
+// with a background context.
 func (e *ErrandsAPI) RequestErrandToProcess(topic string) (*ErrandResponse, error) {
-	client := &http.Client{}
-	req, err := http.NewRequest("POST", e.EndpointURL+"/v1/errands/process/"+topic, nil)
+	return e.RequestErrandToProcessContext(context.Background(), topic)
+}
+
+// RequestErrandToProcessContext claims the next inactive errand of a type. The context can cancel the request,
+// including while it waits for the rate limiter.
+func (e *ErrandsAPI) RequestErrandToProcessContext(ctx context.Context, topic string) (*ErrandResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", e.EndpointURL+"/v1/errands/process/"+topic, nil)
 	if err != nil {
 		return &ErrandResponse{}, err
 	}
-	resp, err := client.Do(req)
+	resp, err := e.do(EndpointClaim, req)
 	if err != nil {
 		return &ErrandResponse{}, err
 	}
@@ -90,19 +169,24 @@ type FailErrandReq struct {
 	Reason string `json:"reason"`
 }
 
+// FailErrand marks an errand as failed. It's the same as FailErrandContext with a background context.
 func (e *ErrandsAPI) FailErrand(errandId, reason string) (*ErrandResponse, error) {
+	return e.FailErrandContext(context.Background(), errandId, reason)
+}
+
+// FailErrandContext marks an errand as failed. The context can cancel the request, including while it waits for the rate limiter.
+func (e *ErrandsAPI) FailErrandContext(ctx context.Context, errandId, reason string) (*ErrandResponse, error) {
 	failReq := &FailErrandReq{reason}
 	failReqBytes, err := failReq.MarshalJSON()
 	if err != nil {
 		return &ErrandResponse{}, err
 	}
-	client := &http.Client{}
-	req, err := http.NewRequest("PUT", e.EndpointURL+"/v1/errand/"+errandId+"/failed", bytes.NewBuffer(failReqBytes))
+	req, err := http.NewRequestWithContext(ctx, "PUT", e.EndpointURL+"/v1/errand/"+errandId+"/failed", bytes.NewBuffer(failReqBytes))
 	if err != nil {
 		return &ErrandResponse{}, err
 	}
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
-	resp, err := client.Do(req)
+	resp, err := e.do(EndpointReport, req)
 	if err != nil {
 		return &ErrandResponse{}, err
 	}
@@ -119,19 +203,62 @@ type CompleteErrandReq struct {
 	Results map[string]interface{} `json:"results"`
 }
 
+// CompleteErrand marks an errand as completed. It's the same as CompleteErrandContext with a background context.
 func (e *ErrandsAPI) CompleteErrand(errandId string, results map[string]interface{}) (*ErrandResponse, error) {
+	return e.CompleteErrandContext(context.Background(), errandId, results)
+}
+
+// CompleteErrandContext marks an errand as completed. The context can cancel the request, including while it waits
+// for the rate limiter.
+func (e *ErrandsAPI) CompleteErrandContext(ctx context.Context, errandId string, results map[string]interface{}) (*ErrandResponse, error) {
 	compReq := &CompleteErrandReq{results}
 	compReqBytes, err := compReq.MarshalJSON()
 	if err != nil {
 		return &ErrandResponse{}, err
 	}
-	client := &http.Client{}
-	req, err := http.NewRequest("PUT", e.EndpointURL+"/v1/errand/"+errandId+"/completed", bytes.NewBuffer(compReqBytes))
+	req, err := http.NewRequestWithContext(ctx, "PUT", e.EndpointURL+"/v1/errand/"+errandId+"/completed", bytes.NewBuffer(compReqBytes))
+	if err != nil {
+		return &ErrandResponse{}, err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	resp, err := e.do(EndpointReport, req)
+	if err != nil {
+		return &ErrandResponse{}, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return &ErrandResponse{}, err
+	}
+	return parseErrandResponse(body)
+}
+
+//easyjson:json
+type UpdateErrandReq struct {
+	Progress float64  `json:"progress"`
+	Logs     []string `json:"logs,omitempty"`
+}
+
+// UpdateErrand updates the progress of an active errand. A progress of 0 leaves the progress unchanged,
+// which makes it useful as a heartbeat. The server responds with a non-OK status if the errand is no longer active.
+// It's the same as UpdateErrandContext with a background context.
+func (e *ErrandsAPI) UpdateErrand(errandId string, progress float64) (*ErrandResponse, error) {
+	return e.UpdateErrandContext(context.Background(), errandId, progress)
+}
+
+// UpdateErrandContext is UpdateErrand with a context that can cancel the request, including while it waits for the rate limiter.
+func (e *ErrandsAPI) UpdateErrandContext(ctx context.Context, errandId string, progress float64) (*ErrandResponse, error) {
+	updateReq := &UpdateErrandReq{Progress: progress}
+	updateReqBytes, err := updateReq.MarshalJSON()
+	if err != nil {
+		return &ErrandResponse{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "PUT", e.EndpointURL+"/v1/errand/"+errandId, bytes.NewBuffer(updateReqBytes))
 	if err != nil {
 		return &ErrandResponse{}, err
 	}
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
-	resp, err := client.Do(req)
+	resp, err := e.do(EndpointReport, req)
 	if err != nil {
 		return &ErrandResponse{}, err
 	}
@@ -143,13 +270,18 @@ func (e *ErrandsAPI) CompleteErrand(errandId string, results map[string]interfac
 	return parseErrandResponse(body)
 }
 
+// DeleteErrand deletes an errand. It's the same as DeleteErrandContext with a background context.
 func (e *ErrandsAPI) DeleteErrand(errandId string) (*ErrandResponse, error) {
-	client := &http.Client{}
-	req, err := http.NewRequest("DELETE", e.EndpointURL+"/v1/errand/"+errandId, nil)
+	return e.DeleteErrandContext(context.Background(), errandId)
+}
+
+// DeleteErrandContext deletes an errand. The context can cancel the request, including while it waits for the rate limiter.
+func (e *ErrandsAPI) DeleteErrandContext(ctx context.Context, errandId string) (*ErrandResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, "DELETE", e.EndpointURL+"/v1/errand/"+errandId, nil)
 	if err != nil {
 		return &ErrandResponse{}, err
 	}
-	resp, err := client.Do(req)
+	resp, err := e.do(EndpointOther, req)
 	if err != nil {
 		return &ErrandResponse{}, err
 	}
@@ -161,6 +293,26 @@ func (e *ErrandsAPI) DeleteErrand(errandId string) (*ErrandResponse, error) {
 	return parseErrandResponse(body)
 }
 
+// RetryErrand puts a failed or completed errand back into the inactive state so it will be processed again.
+// If the errand is part of a pipeline, its blocked dependents will run once it completes.
+func (e *ErrandsAPI) RetryErrand(ctx context.Context, errandID string) (*ErrandResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.EndpointURL+"/v1/errand/"+errandID+"/retry", nil)
+	if err != nil {
+		return nil, fmt.Errorf("new http request: %w", err)
+	}
+
+	response := &ErrandResponse{}
+	if err := e.requestAndUnmarshalResponse(EndpointOther, req, response); err != nil {
+		return nil, err
+	}
+
+	if response.Status != "OK" {
+		return nil, fmt.Errorf("retry errand %s: response status %q", errandID, response.Status)
+	}
+
+	return response, nil
+}
+
 func parseErrandResponse(res []byte) (*ErrandResponse, error) {
 	errandRes := &ErrandResponse{}
 	if err := errandRes.UnmarshalJSON(res); err != nil {
@@ -177,14 +329,43 @@ func parseErrandsResponse(res []byte) (*ErrandsResponse, error) {
 	return errandRes, nil
 }
 
-func (e *ErrandsAPI) get(url string) ([]byte, error) {
-	resp, err := http.Get(e.EndpointURL + url)
+func (e *ErrandsAPI) get(ctx context.Context, class EndpointClass, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", e.EndpointURL+url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := e.do(class, req)
 	if err != nil {
 		return nil, err
 	}
+	defer resp.Body.Close()
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		return nil, err
 	}
 	return body, nil
 }
+
+// do sends req, with the headers set with SetHeader, once the circuit breaker and the rate limiter for its
+// endpoint class allow it.
+func (e *ErrandsAPI) do(class EndpointClass, req *http.Request) (*http.Response, error) {
+	for key, values := range e.headers {
+		req.Header[key] = values
+	}
+	if e.breaker != nil {
+		if err := e.breaker.allow(); err != nil {
+			return nil, err
+		}
+	}
+	if err := e.waitForRateLimit(req.Context(), class); err != nil {
+		if e.breaker != nil {
+			e.breaker.abandon()
+		}
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if e.breaker != nil {
+		e.breaker.record(resp, err)
+	}
+	return resp, err
+}
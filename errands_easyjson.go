@@ -18,7 +18,222 @@ var (
 	_ easyjson.Marshaler
 )
 
-func easyjson50e08061DecodeGithubComPolygonIoErrandsGo(in *jlexer.Lexer, out *ListPipelineResponse) {
+func easyjson50e08061DecodeGithubComPolygonIoErrandsGo(in *jlexer.Lexer, out *UpdateErrandReq) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "progress":
+			out.Progress = float64(in.Float64())
+		case "logs":
+			if in.IsNull() {
+				in.Skip()
+				out.Logs = nil
+			} else {
+				in.Delim('[')
+				if out.Logs == nil {
+					if !in.IsDelim(']') {
+						out.Logs = make([]string, 0, 4)
+					} else {
+						out.Logs = []string{}
+					}
+				} else {
+					out.Logs = (out.Logs)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 string
+					v1 = string(in.String())
+					out.Logs = append(out.Logs, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson50e08061EncodeGithubComPolygonIoErrandsGo(out *jwriter.Writer, in UpdateErrandReq) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"progress\":"
+		out.RawString(prefix[1:])
+		out.Float64(float64(in.Progress))
+	}
+	if len(in.Logs) != 0 {
+		const prefix string = ",\"logs\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v2, v3 := range in.Logs {
+				if v2 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v3))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v UpdateErrandReq) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson50e08061EncodeGithubComPolygonIoErrandsGo(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v UpdateErrandReq) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson50e08061EncodeGithubComPolygonIoErrandsGo(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *UpdateErrandReq) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson50e08061DecodeGithubComPolygonIoErrandsGo(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *UpdateErrandReq) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson50e08061DecodeGithubComPolygonIoErrandsGo(l, v)
+}
+func easyjson50e08061DecodeGithubComPolygonIoErrandsGo1(in *jlexer.Lexer, out *ProcessorStatus) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "topic":
+			out.Topic = string(in.String())
+		case "running":
+			out.Running = bool(in.Bool())
+		case "paused":
+			out.Paused = bool(in.Bool())
+		case "lastSuccessfulPoll":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.LastSuccessfulPoll).UnmarshalJSON(data))
+			}
+		case "inFlight":
+			out.InFlight = int(in.Int())
+		case "consecutiveErrors":
+			out.ConsecutiveErrors = int(in.Int())
+		case "failingSince":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.FailingSince).UnmarshalJSON(data))
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson50e08061EncodeGithubComPolygonIoErrandsGo1(out *jwriter.Writer, in ProcessorStatus) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"topic\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Topic))
+	}
+	{
+		const prefix string = ",\"running\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Running))
+	}
+	{
+		const prefix string = ",\"paused\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Paused))
+	}
+	{
+		const prefix string = ",\"lastSuccessfulPoll\":"
+		out.RawString(prefix)
+		out.Raw((in.LastSuccessfulPoll).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"inFlight\":"
+		out.RawString(prefix)
+		out.Int(int(in.InFlight))
+	}
+	{
+		const prefix string = ",\"consecutiveErrors\":"
+		out.RawString(prefix)
+		out.Int(int(in.ConsecutiveErrors))
+	}
+	{
+		const prefix string = ",\"failingSince\":"
+		out.RawString(prefix)
+		out.Raw((in.FailingSince).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ProcessorStatus) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson50e08061EncodeGithubComPolygonIoErrandsGo1(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ProcessorStatus) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson50e08061EncodeGithubComPolygonIoErrandsGo1(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ProcessorStatus) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson50e08061DecodeGithubComPolygonIoErrandsGo1(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ProcessorStatus) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson50e08061DecodeGithubComPolygonIoErrandsGo1(l, v)
+}
+func easyjson50e08061DecodeGithubComPolygonIoErrandsGo2(in *jlexer.Lexer, out *ListPipelineResponse) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -53,17 +268,17 @@ func easyjson50e08061DecodeGithubComPolygonIoErrandsGo(in *jlexer.Lexer, out *Li
 					out.Results = (out.Results)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v1 *schemas.Pipeline
+					var v4 *schemas.Pipeline
 					if in.IsNull() {
 						in.Skip()
-						v1 = nil
+						v4 = nil
 					} else {
-						if v1 == nil {
-							v1 = new(schemas.Pipeline)
+						if v4 == nil {
+							v4 = new(schemas.Pipeline)
 						}
-						(*v1).UnmarshalEasyJSON(in)
+						(*v4).UnmarshalEasyJSON(in)
 					}
-					out.Results = append(out.Results, v1)
+					out.Results = append(out.Results, v4)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -80,7 +295,7 @@ func easyjson50e08061DecodeGithubComPolygonIoErrandsGo(in *jlexer.Lexer, out *Li
 		in.Consumed()
 	}
 }
-func easyjson50e08061EncodeGithubComPolygonIoErrandsGo(out *jwriter.Writer, in ListPipelineResponse) {
+func easyjson50e08061EncodeGithubComPolygonIoErrandsGo2(out *jwriter.Writer, in ListPipelineResponse) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -91,14 +306,14 @@ func easyjson50e08061EncodeGithubComPolygonIoErrandsGo(out *jwriter.Writer, in L
 			out.RawString("null")
 		} else {
 			out.RawByte('[')
-			for v2, v3 := range in.Results {
-				if v2 > 0 {
+			for v5, v6 := range in.Results {
+				if v5 > 0 {
 					out.RawByte(',')
 				}
-				if v3 == nil {
+				if v6 == nil {
 					out.RawString("null")
 				} else {
-					(*v3).MarshalEasyJSON(out)
+					(*v6).MarshalEasyJSON(out)
 				}
 			}
 			out.RawByte(']')
@@ -115,27 +330,153 @@ func easyjson50e08061EncodeGithubComPolygonIoErrandsGo(out *jwriter.Writer, in L
 // MarshalJSON supports json.Marshaler interface
 func (v ListPipelineResponse) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson50e08061EncodeGithubComPolygonIoErrandsGo(&w, v)
+	easyjson50e08061EncodeGithubComPolygonIoErrandsGo2(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v ListPipelineResponse) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson50e08061EncodeGithubComPolygonIoErrandsGo(w, v)
+	easyjson50e08061EncodeGithubComPolygonIoErrandsGo2(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *ListPipelineResponse) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson50e08061DecodeGithubComPolygonIoErrandsGo(&r, v)
+	easyjson50e08061DecodeGithubComPolygonIoErrandsGo2(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *ListPipelineResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson50e08061DecodeGithubComPolygonIoErrandsGo(l, v)
+	easyjson50e08061DecodeGithubComPolygonIoErrandsGo2(l, v)
+}
+func easyjson50e08061DecodeGithubComPolygonIoErrandsGo3(in *jlexer.Lexer, out *HealthResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "ready":
+			out.Ready = bool(in.Bool())
+		case "live":
+			out.Live = bool(in.Bool())
+		case "circuitOpen":
+			out.CircuitOpen = bool(in.Bool())
+		case "processors":
+			if in.IsNull() {
+				in.Skip()
+				out.Processors = nil
+			} else {
+				in.Delim('[')
+				if out.Processors == nil {
+					if !in.IsDelim(']') {
+						out.Processors = make([]ProcessorStatus, 0, 1)
+					} else {
+						out.Processors = []ProcessorStatus{}
+					}
+				} else {
+					out.Processors = (out.Processors)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v7 ProcessorStatus
+					(v7).UnmarshalEasyJSON(in)
+					out.Processors = append(out.Processors, v7)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "status":
+			out.Status = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson50e08061EncodeGithubComPolygonIoErrandsGo3(out *jwriter.Writer, in HealthResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"ready\":"
+		out.RawString(prefix[1:])
+		out.Bool(bool(in.Ready))
+	}
+	{
+		const prefix string = ",\"live\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Live))
+	}
+	{
+		const prefix string = ",\"circuitOpen\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.CircuitOpen))
+	}
+	{
+		const prefix string = ",\"processors\":"
+		out.RawString(prefix)
+		if in.Processors == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v8, v9 := range in.Processors {
+				if v8 > 0 {
+					out.RawByte(',')
+				}
+				(v9).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"status\":"
+		out.RawString(prefix)
+		out.String(string(in.Status))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v HealthResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson50e08061EncodeGithubComPolygonIoErrandsGo3(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v HealthResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson50e08061EncodeGithubComPolygonIoErrandsGo3(w, v)
 }
-func easyjson50e08061DecodeGithubComPolygonIoErrandsGo1(in *jlexer.Lexer, out *GetPipelineResponse) {
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *HealthResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson50e08061DecodeGithubComPolygonIoErrandsGo3(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *HealthResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson50e08061DecodeGithubComPolygonIoErrandsGo3(l, v)
+}
+func easyjson50e08061DecodeGithubComPolygonIoErrandsGo4(in *jlexer.Lexer, out *GetPipelineResponse) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -168,7 +509,7 @@ func easyjson50e08061DecodeGithubComPolygonIoErrandsGo1(in *jlexer.Lexer, out *G
 		in.Consumed()
 	}
 }
-func easyjson50e08061EncodeGithubComPolygonIoErrandsGo1(out *jwriter.Writer, in GetPipelineResponse) {
+func easyjson50e08061EncodeGithubComPolygonIoErrandsGo4(out *jwriter.Writer, in GetPipelineResponse) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -188,27 +529,27 @@ func easyjson50e08061EncodeGithubComPolygonIoErrandsGo1(out *jwriter.Writer, in
 // MarshalJSON supports json.Marshaler interface
 func (v GetPipelineResponse) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson50e08061EncodeGithubComPolygonIoErrandsGo1(&w, v)
+	easyjson50e08061EncodeGithubComPolygonIoErrandsGo4(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v GetPipelineResponse) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson50e08061EncodeGithubComPolygonIoErrandsGo1(w, v)
+	easyjson50e08061EncodeGithubComPolygonIoErrandsGo4(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *GetPipelineResponse) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson50e08061DecodeGithubComPolygonIoErrandsGo1(&r, v)
+	easyjson50e08061DecodeGithubComPolygonIoErrandsGo4(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *GetPipelineResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson50e08061DecodeGithubComPolygonIoErrandsGo1(l, v)
+	easyjson50e08061DecodeGithubComPolygonIoErrandsGo4(l, v)
 }
-func easyjson50e08061DecodeGithubComPolygonIoErrandsGo2(in *jlexer.Lexer, out *FailErrandReq) {
+func easyjson50e08061DecodeGithubComPolygonIoErrandsGo5(in *jlexer.Lexer, out *FailErrandReq) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -239,7 +580,7 @@ func easyjson50e08061DecodeGithubComPolygonIoErrandsGo2(in *jlexer.Lexer, out *F
 		in.Consumed()
 	}
 }
-func easyjson50e08061EncodeGithubComPolygonIoErrandsGo2(out *jwriter.Writer, in FailErrandReq) {
+func easyjson50e08061EncodeGithubComPolygonIoErrandsGo5(out *jwriter.Writer, in FailErrandReq) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -254,27 +595,27 @@ func easyjson50e08061EncodeGithubComPolygonIoErrandsGo2(out *jwriter.Writer, in
 // MarshalJSON supports json.Marshaler interface
 func (v FailErrandReq) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson50e08061EncodeGithubComPolygonIoErrandsGo2(&w, v)
+	easyjson50e08061EncodeGithubComPolygonIoErrandsGo5(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v FailErrandReq) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson50e08061EncodeGithubComPolygonIoErrandsGo2(w, v)
+	easyjson50e08061EncodeGithubComPolygonIoErrandsGo5(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *FailErrandReq) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson50e08061DecodeGithubComPolygonIoErrandsGo2(&r, v)
+	easyjson50e08061DecodeGithubComPolygonIoErrandsGo5(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *FailErrandReq) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson50e08061DecodeGithubComPolygonIoErrandsGo2(l, v)
+	easyjson50e08061DecodeGithubComPolygonIoErrandsGo5(l, v)
 }
-func easyjson50e08061DecodeGithubComPolygonIoErrandsGo3(in *jlexer.Lexer, out *ErrandsResponse) {
+func easyjson50e08061DecodeGithubComPolygonIoErrandsGo6(in *jlexer.Lexer, out *ErrandsResponse) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -309,9 +650,9 @@ func easyjson50e08061DecodeGithubComPolygonIoErrandsGo3(in *jlexer.Lexer, out *E
 					out.Results = (out.Results)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v4 schemas.Errand
-					(v4).UnmarshalEasyJSON(in)
-					out.Results = append(out.Results, v4)
+					var v10 schemas.Errand
+					(v10).UnmarshalEasyJSON(in)
+					out.Results = append(out.Results, v10)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -328,7 +669,7 @@ func easyjson50e08061DecodeGithubComPolygonIoErrandsGo3(in *jlexer.Lexer, out *E
 		in.Consumed()
 	}
 }
-func easyjson50e08061EncodeGithubComPolygonIoErrandsGo3(out *jwriter.Writer, in ErrandsResponse) {
+func easyjson50e08061EncodeGithubComPolygonIoErrandsGo6(out *jwriter.Writer, in ErrandsResponse) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -339,11 +680,11 @@ func easyjson50e08061EncodeGithubComPolygonIoErrandsGo3(out *jwriter.Writer, in
 			out.RawString("null")
 		} else {
 			out.RawByte('[')
-			for v5, v6 := range in.Results {
-				if v5 > 0 {
+			for v11, v12 := range in.Results {
+				if v11 > 0 {
 					out.RawByte(',')
 				}
-				(v6).MarshalEasyJSON(out)
+				(v12).MarshalEasyJSON(out)
 			}
 			out.RawByte(']')
 		}
@@ -359,27 +700,27 @@ func easyjson50e08061EncodeGithubComPolygonIoErrandsGo3(out *jwriter.Writer, in
 // MarshalJSON supports json.Marshaler interface
 func (v ErrandsResponse) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson50e08061EncodeGithubComPolygonIoErrandsGo3(&w, v)
+	easyjson50e08061EncodeGithubComPolygonIoErrandsGo6(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v ErrandsResponse) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson50e08061EncodeGithubComPolygonIoErrandsGo3(w, v)
+	easyjson50e08061EncodeGithubComPolygonIoErrandsGo6(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *ErrandsResponse) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson50e08061DecodeGithubComPolygonIoErrandsGo3(&r, v)
+	easyjson50e08061DecodeGithubComPolygonIoErrandsGo6(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *ErrandsResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson50e08061DecodeGithubComPolygonIoErrandsGo3(l, v)
+	easyjson50e08061DecodeGithubComPolygonIoErrandsGo6(l, v)
 }
-func easyjson50e08061DecodeGithubComPolygonIoErrandsGo4(in *jlexer.Lexer, out *ErrandResponse) {
+func easyjson50e08061DecodeGithubComPolygonIoErrandsGo7(in *jlexer.Lexer, out *ErrandResponse) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -412,7 +753,7 @@ func easyjson50e08061DecodeGithubComPolygonIoErrandsGo4(in *jlexer.Lexer, out *E
 		in.Consumed()
 	}
 }
-func easyjson50e08061EncodeGithubComPolygonIoErrandsGo4(out *jwriter.Writer, in ErrandResponse) {
+func easyjson50e08061EncodeGithubComPolygonIoErrandsGo7(out *jwriter.Writer, in ErrandResponse) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -432,27 +773,27 @@ func easyjson50e08061EncodeGithubComPolygonIoErrandsGo4(out *jwriter.Writer, in
 // MarshalJSON supports json.Marshaler interface
 func (v ErrandResponse) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson50e08061EncodeGithubComPolygonIoErrandsGo4(&w, v)
+	easyjson50e08061EncodeGithubComPolygonIoErrandsGo7(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v ErrandResponse) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson50e08061EncodeGithubComPolygonIoErrandsGo4(w, v)
+	easyjson50e08061EncodeGithubComPolygonIoErrandsGo7(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *ErrandResponse) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson50e08061DecodeGithubComPolygonIoErrandsGo4(&r, v)
+	easyjson50e08061DecodeGithubComPolygonIoErrandsGo7(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *ErrandResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson50e08061DecodeGithubComPolygonIoErrandsGo4(l, v)
+	easyjson50e08061DecodeGithubComPolygonIoErrandsGo7(l, v)
 }
-func easyjson50e08061DecodeGithubComPolygonIoErrandsGo5(in *jlexer.Lexer, out *DeletePipelineResponse) {
+func easyjson50e08061DecodeGithubComPolygonIoErrandsGo8(in *jlexer.Lexer, out *DeletePipelineResponse) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -483,7 +824,7 @@ func easyjson50e08061DecodeGithubComPolygonIoErrandsGo5(in *jlexer.Lexer, out *D
 		in.Consumed()
 	}
 }
-func easyjson50e08061EncodeGithubComPolygonIoErrandsGo5(out *jwriter.Writer, in DeletePipelineResponse) {
+func easyjson50e08061EncodeGithubComPolygonIoErrandsGo8(out *jwriter.Writer, in DeletePipelineResponse) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -498,27 +839,27 @@ func easyjson50e08061EncodeGithubComPolygonIoErrandsGo5(out *jwriter.Writer, in
 // MarshalJSON supports json.Marshaler interface
 func (v DeletePipelineResponse) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson50e08061EncodeGithubComPolygonIoErrandsGo5(&w, v)
+	easyjson50e08061EncodeGithubComPolygonIoErrandsGo8(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v DeletePipelineResponse) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson50e08061EncodeGithubComPolygonIoErrandsGo5(w, v)
+	easyjson50e08061EncodeGithubComPolygonIoErrandsGo8(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *DeletePipelineResponse) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson50e08061DecodeGithubComPolygonIoErrandsGo5(&r, v)
+	easyjson50e08061DecodeGithubComPolygonIoErrandsGo8(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *DeletePipelineResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson50e08061DecodeGithubComPolygonIoErrandsGo5(l, v)
+	easyjson50e08061DecodeGithubComPolygonIoErrandsGo8(l, v)
 }
-func easyjson50e08061DecodeGithubComPolygonIoErrandsGo6(in *jlexer.Lexer, out *CreatePipelineResponse) {
+func easyjson50e08061DecodeGithubComPolygonIoErrandsGo9(in *jlexer.Lexer, out *CreatePipelineResponse) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -551,7 +892,7 @@ func easyjson50e08061DecodeGithubComPolygonIoErrandsGo6(in *jlexer.Lexer, out *C
 		in.Consumed()
 	}
 }
-func easyjson50e08061EncodeGithubComPolygonIoErrandsGo6(out *jwriter.Writer, in CreatePipelineResponse) {
+func easyjson50e08061EncodeGithubComPolygonIoErrandsGo9(out *jwriter.Writer, in CreatePipelineResponse) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -571,27 +912,27 @@ func easyjson50e08061EncodeGithubComPolygonIoErrandsGo6(out *jwriter.Writer, in
 // MarshalJSON supports json.Marshaler interface
 func (v CreatePipelineResponse) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson50e08061EncodeGithubComPolygonIoErrandsGo6(&w, v)
+	easyjson50e08061EncodeGithubComPolygonIoErrandsGo9(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v CreatePipelineResponse) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson50e08061EncodeGithubComPolygonIoErrandsGo6(w, v)
+	easyjson50e08061EncodeGithubComPolygonIoErrandsGo9(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *CreatePipelineResponse) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson50e08061DecodeGithubComPolygonIoErrandsGo6(&r, v)
+	easyjson50e08061DecodeGithubComPolygonIoErrandsGo9(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *CreatePipelineResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson50e08061DecodeGithubComPolygonIoErrandsGo6(l, v)
+	easyjson50e08061DecodeGithubComPolygonIoErrandsGo9(l, v)
 }
-func easyjson50e08061DecodeGithubComPolygonIoErrandsGo7(in *jlexer.Lexer, out *CompleteErrandReq) {
+func easyjson50e08061DecodeGithubComPolygonIoErrandsGo10(in *jlexer.Lexer, out *CompleteErrandReq) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -619,15 +960,15 @@ func easyjson50e08061DecodeGithubComPolygonIoErrandsGo7(in *jlexer.Lexer, out *C
 				for !in.IsDelim('}') {
 					key := string(in.String())
 					in.WantColon()
-					var v7 interface{}
-					if m, ok := v7.(easyjson.Unmarshaler); ok {
+					var v13 interface{}
+					if m, ok := v13.(easyjson.Unmarshaler); ok {
 						m.UnmarshalEasyJSON(in)
-					} else if m, ok := v7.(json.Unmarshaler); ok {
+					} else if m, ok := v13.(json.Unmarshaler); ok {
 						_ = m.UnmarshalJSON(in.Raw())
 					} else {
-						v7 = in.Interface()
+						v13 = in.Interface()
 					}
-					(out.Results)[key] = v7
+					(out.Results)[key] = v13
 					in.WantComma()
 				}
 				in.Delim('}')
@@ -642,7 +983,7 @@ func easyjson50e08061DecodeGithubComPolygonIoErrandsGo7(in *jlexer.Lexer, out *C
 		in.Consumed()
 	}
 }
-func easyjson50e08061EncodeGithubComPolygonIoErrandsGo7(out *jwriter.Writer, in CompleteErrandReq) {
+func easyjson50e08061EncodeGithubComPolygonIoErrandsGo10(out *jwriter.Writer, in CompleteErrandReq) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -653,21 +994,21 @@ func easyjson50e08061EncodeGithubComPolygonIoErrandsGo7(out *jwriter.Writer, in
 			out.RawString(`null`)
 		} else {
 			out.RawByte('{')
-			v8First := true
-			for v8Name, v8Value := range in.Results {
-				if v8First {
-					v8First = false
+			v14First := true
+			for v14Name, v14Value := range in.Results {
+				if v14First {
+					v14First = false
 				} else {
 					out.RawByte(',')
 				}
-				out.String(string(v8Name))
+				out.String(string(v14Name))
 				out.RawByte(':')
-				if m, ok := v8Value.(easyjson.Marshaler); ok {
+				if m, ok := v14Value.(easyjson.Marshaler); ok {
 					m.MarshalEasyJSON(out)
-				} else if m, ok := v8Value.(json.Marshaler); ok {
+				} else if m, ok := v14Value.(json.Marshaler); ok {
 					out.Raw(m.MarshalJSON())
 				} else {
-					out.Raw(json.Marshal(v8Value))
+					out.Raw(json.Marshal(v14Value))
 				}
 			}
 			out.RawByte('}')
@@ -679,23 +1020,23 @@ func easyjson50e08061EncodeGithubComPolygonIoErrandsGo7(out *jwriter.Writer, in
 // MarshalJSON supports json.Marshaler interface
 func (v CompleteErrandReq) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson50e08061EncodeGithubComPolygonIoErrandsGo7(&w, v)
+	easyjson50e08061EncodeGithubComPolygonIoErrandsGo10(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v CompleteErrandReq) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson50e08061EncodeGithubComPolygonIoErrandsGo7(w, v)
+	easyjson50e08061EncodeGithubComPolygonIoErrandsGo10(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *CompleteErrandReq) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson50e08061DecodeGithubComPolygonIoErrandsGo7(&r, v)
+	easyjson50e08061DecodeGithubComPolygonIoErrandsGo10(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *CompleteErrandReq) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson50e08061DecodeGithubComPolygonIoErrandsGo7(l, v)
+	easyjson50e08061DecodeGithubComPolygonIoErrandsGo10(l, v)
 }
@@ -1,6 +1,8 @@
 package errands
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -64,3 +66,23 @@ func TestDeleteErrand(t *testing.T) {
 	}
 	log.Println("Deleted Errand:", errandRes)
 }
+
+func TestSetHeader(t *testing.T) {
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		w.Write([]byte(`{"status": "OK"}`))
+	}))
+	defer server.Close()
+
+	api := New(server.URL)
+	api.SetHeader("authorization", "Bearer abc")
+
+	if _, err := api.DeleteErrand("abc"); err != nil {
+		t.Fatal(err)
+	}
+
+	if auth != "Bearer abc" {
+		t.Errorf("expected the Authorization header to be sent, got %q", auth)
+	}
+}
@@ -0,0 +1,120 @@
+package errands
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/polygon-io/errands-server/schemas"
+)
+
+// fakeServer mimics the errand routes of errands-server v1.1.0, including the quirks clients have to live with:
+// GET /v1/errand/:id is routed to the create handler, so without a body it fails validation with a 400, and
+// completing an errand doesn't keep the results it was completed with.
+type fakeServer struct {
+	mu      sync.Mutex
+	errands []schemas.Errand
+}
+
+func (s *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := r.URL.Path
+	switch {
+	case r.Method == http.MethodPost && path == "/v1/errands/":
+		var errand schemas.Errand
+		if err := json.NewDecoder(r.Body).Decode(&errand); err != nil {
+			s.validationFailed(w, err)
+			return
+		}
+
+		errand.ID = fmt.Sprintf("e%d", len(s.errands)+1)
+		errand.Status = schemas.StatusInactive
+		errand.Created = time.Now().UnixMilli()
+		s.errands = append(s.errands, errand)
+		s.writeErrand(w, &errand)
+
+	case r.Method == http.MethodGet && path == "/v1/errands/":
+		body, _ := (&ErrandsResponse{Status: "OK", Results: s.errands}).MarshalJSON()
+		w.Write(body)
+
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "/v1/errand/"):
+		s.validationFailed(w, fmt.Errorf("EOF"))
+
+	case r.Method == http.MethodPut && strings.HasSuffix(path, "/completed"):
+		errand := s.find(path, "/completed")
+		if errand == nil {
+			s.internalError(w)
+			return
+		}
+
+		errand.Status = schemas.StatusCompleted
+		errand.Completed = time.Now().UnixMilli()
+		errand.Progress = 100
+		s.writeErrand(w, errand)
+
+	case r.Method == http.MethodPut && strings.HasSuffix(path, "/failed"):
+		var failed FailErrandReq
+		if err := json.NewDecoder(r.Body).Decode(&failed); err != nil || failed.Reason == "" {
+			s.validationFailed(w, fmt.Errorf("reason is required"))
+			return
+		}
+
+		errand := s.find(path, "/failed")
+		if errand == nil {
+			s.internalError(w)
+			return
+		}
+
+		errand.Status = schemas.StatusFailed
+		errand.Failed = time.Now().UnixMilli()
+		errand.Logs = append(errand.Logs, schemas.Log{Severity: "ERROR", Message: failed.Reason})
+		s.writeErrand(w, errand)
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 page not found"))
+	}
+}
+
+func (s *fakeServer) find(path, suffix string) *schemas.Errand {
+	id := strings.TrimSuffix(strings.TrimPrefix(path, "/v1/errand/"), suffix)
+	for i := range s.errands {
+		if s.errands[i].ID == id {
+			return &s.errands[i]
+		}
+	}
+
+	return nil
+}
+
+// lastID returns the ID of the most recently created errand, or "" if none have been created.
+func (s *fakeServer) lastID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.errands) == 0 {
+		return ""
+	}
+
+	return s.errands[len(s.errands)-1].ID
+}
+
+func (s *fakeServer) writeErrand(w http.ResponseWriter, errand *schemas.Errand) {
+	body, _ := (&ErrandResponse{Status: "OK", Results: *errand}).MarshalJSON()
+	w.Write(body)
+}
+
+func (s *fakeServer) validationFailed(w http.ResponseWriter, err error) {
+	w.WriteHeader(http.StatusBadRequest)
+	fmt.Fprintf(w, `{"message":"Errand validation failed!","error":%q}`, err.Error())
+}
+
+func (s *fakeServer) internalError(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusInternalServerError)
+	w.Write([]byte(`{"message":"Internal Server Error!","error":"errand not found"}`))
+}
@@ -0,0 +1,177 @@
+package errands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultUnreachableAfter is how long the errands server can be unreachable before a worker reports itself unready.
+const DefaultUnreachableAfter = time.Minute
+
+//easyjson:json
+type ProcessorStatus struct {
+	Topic              string    `json:"topic"`
+	Running            bool      `json:"running"`
+	Paused             bool      `json:"paused"`
+	LastSuccessfulPoll time.Time `json:"lastSuccessfulPoll"`
+	InFlight           int       `json:"inFlight"`
+	ConsecutiveErrors  int       `json:"consecutiveErrors"`
+
+	// FailingSince is when the current run of consecutive API errors started. It's zero if the last poll succeeded.
+	FailingSince time.Time `json:"failingSince"`
+}
+
+//easyjson:json
+type HealthResponse struct {
+	Ready       bool              `json:"ready"`
+	Live        bool              `json:"live"`
+	CircuitOpen bool              `json:"circuitOpen"`
+	Processors  []ProcessorStatus `json:"processors"`
+	Status      string            `json:"status"`
+}
+
+// Status returns a snapshot of the processor's health.
+func (p *Processor) Status() ProcessorStatus {
+	p.health.mu.Lock()
+	defer p.health.mu.Unlock()
+
+	return ProcessorStatus{
+		Topic:              p.Topic,
+		Running:            p.health.running,
+		Paused:             p.Paused,
+		LastSuccessfulPoll: p.health.lastSuccessfulPoll,
+		InFlight:           int(atomic.LoadInt32(&p.inFlight)),
+		ConsecutiveErrors:  p.health.consecutiveErrors,
+		FailingSince:       p.health.failingSince,
+	}
+}
+
+func (p *Processor) setRunning(running bool) {
+	p.health.mu.Lock()
+	defer p.health.mu.Unlock()
+
+	p.health.running = running
+}
+
+func (p *Processor) recordPoll(err error) {
+	p.health.mu.Lock()
+	defer p.health.mu.Unlock()
+
+	if err == nil {
+		p.health.lastSuccessfulPoll = time.Now()
+		p.health.consecutiveErrors = 0
+		p.health.failingSince = time.Time{}
+		return
+	}
+
+	if p.health.consecutiveErrors == 0 {
+		p.health.failingSince = time.Now()
+	}
+	p.health.consecutiveErrors++
+}
+
+// Health reports the status of every processor on this API. The worker is live if all its processors are running,
+// and ready if it's live and none of them has been unable to reach the errands server for longer than unreachableAfter.
+func (e *ErrandsAPI) Health(unreachableAfter time.Duration) *HealthResponse {
+	if unreachableAfter <= 0 {
+		unreachableAfter = DefaultUnreachableAfter
+	}
+
+	health := &HealthResponse{
+		Live:        true,
+		Ready:       true,
+		CircuitOpen: e.CircuitOpen(),
+		Processors:  make([]ProcessorStatus, 0, len(e.Processors)),
+	}
+
+	for _, processor := range e.Processors {
+		status := processor.Status()
+		health.Processors = append(health.Processors, status)
+
+		if !status.Running {
+			health.Live = false
+			health.Ready = false
+		}
+
+		if !status.FailingSince.IsZero() && time.Since(status.FailingSince) > unreachableAfter {
+			health.Ready = false
+		}
+	}
+
+	health.Status = "OK"
+	if !health.Ready {
+		health.Status = "unready"
+	}
+	if !health.Live {
+		health.Status = "down"
+	}
+
+	return health
+}
+
+// HealthHandler returns an http.Handler serving liveness on /healthz and readiness on /readyz.
+// Both respond with a HealthResponse body, with status 200 when healthy and 503 otherwise.
+func (e *ErrandsAPI) HealthHandler(unreachableAfter time.Duration) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		health := e.Health(unreachableAfter)
+		writeHealthResponse(w, health, health.Live)
+	})
+
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		health := e.Health(unreachableAfter)
+		writeHealthResponse(w, health, health.Ready)
+	})
+
+	return mux
+}
+
+func writeHealthResponse(w http.ResponseWriter, health *HealthResponse, ok bool) {
+	body, err := health.MarshalJSON()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if ok {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	w.Write(body)
+}
+
+// ServeHealth runs an HTTP server on addr with the HealthHandler until ctx is cancelled.
+func (e *ErrandsAPI) ServeHealth(ctx context.Context, addr string, unreachableAfter time.Duration) error {
+	server := &http.Server{
+		Addr:    addr,
+		Handler: e.HealthHandler(unreachableAfter),
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errChan:
+		return fmt.Errorf("health server: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("shutdown health server: %w", err)
+	}
+
+	return nil
+}
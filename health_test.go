@@ -0,0 +1,45 @@
+package errands
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHealthHandler(t *testing.T) {
+	api := New("http://localhost:0")
+	processor := &Processor{Parent: api, Topic: "tester"}
+	api.Processors = append(api.Processors, processor)
+
+	handler := api.HealthHandler(time.Minute)
+	check := func(path string, expected int) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != expected {
+			t.Errorf("%s: expected status %d, got %d: %s", path, expected, rec.Code, rec.Body.String())
+		}
+	}
+
+	// Not running yet.
+	check("/healthz", http.StatusServiceUnavailable)
+
+	processor.setRunning(true)
+	processor.recordPoll(nil)
+	check("/healthz", http.StatusOK)
+	check("/readyz", http.StatusOK)
+
+	processor.recordPoll(errors.New("connection refused"))
+	check("/readyz", http.StatusOK)
+
+	processor.health.failingSince = time.Now().Add(-2 * time.Minute)
+	check("/healthz", http.StatusOK)
+	check("/readyz", http.StatusServiceUnavailable)
+
+	status := processor.Status()
+	if status.ConsecutiveErrors != 1 {
+		t.Errorf("expected 1 consecutive error, got %d", status.ConsecutiveErrors)
+	}
+}
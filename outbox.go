@@ -0,0 +1,433 @@
+package errands
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultOutboxReplayInterval is how often unacknowledged outcomes are re-sent when no interval is given.
+const DefaultOutboxReplayInterval = 30 * time.Second
+
+// DefaultOutboxMaxAge is how long an outcome is replayed for before it's dropped, unless SetMaxAge changes it.
+// The server will long since have timed out the errand and handed it to another processor.
+const DefaultOutboxMaxAge = 24 * time.Hour
+
+// errOutcomeRejected is returned by sendOutcome when the server answered, but didn't accept the outcome.
+var errOutcomeRejected = errors.New("outcome rejected")
+
+// OutboxEntry is an errand outcome recorded in the outbox.
+// If Failed is true the errand is reported as failed with Reason, otherwise it's completed with Results.
+type OutboxEntry struct {
+	ErrandID string                 `json:"errandId"`
+	Failed   bool                   `json:"failed,omitempty"`
+	Reason   string                 `json:"reason,omitempty"`
+	Results  map[string]interface{} `json:"results,omitempty"`
+	Recorded int64                  `json:"recorded,omitempty"`
+
+	// Acked marks a line that acknowledges a previously recorded outcome for ErrandID.
+	Acked bool `json:"acked,omitempty"`
+}
+
+// Outbox is an append-only file of errand outcomes. Processors record an outcome before reporting it
+// to the errands server and acknowledge it once the server has received it, so outcomes that could not
+// be reported (network blips, restarts) can be replayed later.
+type Outbox struct {
+	path    string
+	mu      sync.Mutex
+	file    *os.File
+	pending map[string]*OutboxEntry
+	// inFlight holds the IDs of pending outcomes that are being sent, so a replay doesn't send them a second time.
+	inFlight map[string]bool
+	maxAge   time.Duration
+	// acked counts the outcomes acknowledged since the file was last compacted.
+	acked int
+	quit  chan struct{}
+	done  chan struct{}
+}
+
+// OpenOutbox opens the outbox file at path, creating it if it doesn't exist.
+// Any outcomes that were recorded but never acknowledged are loaded so they can be replayed.
+func OpenOutbox(path string) (*Outbox, error) {
+	pending, err := readOutbox(path)
+	if err != nil {
+		return nil, fmt.Errorf("read outbox: %w", err)
+	}
+
+	o := &Outbox{
+		path:     path,
+		pending:  pending,
+		inFlight: make(map[string]bool),
+		maxAge:   DefaultOutboxMaxAge,
+	}
+
+	// Compact the file down to the pending entries so it doesn't grow forever.
+	if err := o.rewrite(); err != nil {
+		return nil, fmt.Errorf("compact outbox: %w", err)
+	}
+
+	return o, nil
+}
+
+func readOutbox(path string) (map[string]*OutboxEntry, error) {
+	pending := make(map[string]*OutboxEntry)
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return pending, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		entry := &OutboxEntry{}
+		if err := json.Unmarshal(scanner.Bytes(), entry); err != nil {
+			// A torn write at the end of the file is expected after a crash; anything before it is still good.
+			fmt.Println("Skipping unreadable outbox line:", err)
+			continue
+		}
+
+		if entry.Acked {
+			delete(pending, entry.ErrandID)
+		} else {
+			pending[entry.ErrandID] = entry
+		}
+	}
+
+	return pending, scanner.Err()
+}
+
+// rewrite replaces the outbox file with one containing only the pending entries.
+func (o *Outbox) rewrite() error {
+	tmpPath := o.path + ".tmp"
+	tmp, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range o.sortedPending() {
+		if err := writeOutboxLine(tmp, entry); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmpPath, o.path); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(o.path, os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	if o.file != nil {
+		o.file.Close()
+	}
+	o.file = file
+	o.acked = 0
+
+	return nil
+}
+
+// compact rewrites the outbox file down to the pending entries if any outcomes have been acknowledged since it was
+// last compacted, so a long running processor's outbox doesn't grow forever.
+func (o *Outbox) compact() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.acked == 0 {
+		return nil
+	}
+
+	return o.rewrite()
+}
+
+// SetMaxAge sets how long after an outcome was recorded it's replayed for, after which it's dropped.
+// A max age of 0 or less replays outcomes until the server accepts them.
+func (o *Outbox) SetMaxAge(maxAge time.Duration) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.maxAge = maxAge
+}
+
+// expired reports whether entry was recorded longer ago than the max age.
+func (o *Outbox) expired(entry *OutboxEntry) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.maxAge > 0 && time.Since(time.UnixMilli(entry.Recorded)) > o.maxAge
+}
+
+func writeOutboxLine(f *os.File, entry *OutboxEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+func (o *Outbox) append(entry *OutboxEntry) error {
+	if err := writeOutboxLine(o.file, entry); err != nil {
+		return err
+	}
+
+	return o.file.Sync()
+}
+
+// Record durably stores an outcome. It must be called before the outcome is reported to the server.
+func (o *Outbox) Record(entry *OutboxEntry) error {
+	return o.record(entry, false)
+}
+
+// record stores an outcome, marking it in flight if inFlight is set so that it isn't replayed while it's being sent.
+func (o *Outbox) record(entry *OutboxEntry, inFlight bool) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if entry.Recorded == 0 {
+		entry.Recorded = time.Now().UnixMilli()
+	}
+
+	if err := o.append(entry); err != nil {
+		return err
+	}
+
+	o.pending[entry.ErrandID] = entry
+	if inFlight {
+		o.inFlight[entry.ErrandID] = true
+	}
+
+	return nil
+}
+
+// begin marks the pending outcome for errandID as being sent. It returns false if it's already being sent
+// or is no longer pending.
+func (o *Outbox) begin(errandID string) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if _, exists := o.pending[errandID]; !exists || o.inFlight[errandID] {
+		return false
+	}
+
+	o.inFlight[errandID] = true
+	return true
+}
+
+// end marks the outcome for errandID as no longer being sent.
+func (o *Outbox) end(errandID string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	delete(o.inFlight, errandID)
+}
+
+// Ack marks the outcome for errandID as received by the server so it won't be replayed.
+func (o *Outbox) Ack(errandID string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if _, exists := o.pending[errandID]; !exists {
+		return nil
+	}
+
+	if err := o.append(&OutboxEntry{ErrandID: errandID, Acked: true}); err != nil {
+		return err
+	}
+
+	delete(o.pending, errandID)
+	o.acked++
+	return nil
+}
+
+// Pending returns the outcomes that have been recorded but not acknowledged, oldest first.
+func (o *Outbox) Pending() []*OutboxEntry {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.sortedPending()
+}
+
+func (o *Outbox) sortedPending() []*OutboxEntry {
+	entries := make([]*OutboxEntry, 0, len(o.pending))
+	for _, entry := range o.pending {
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Recorded < entries[j].Recorded
+	})
+
+	return entries
+}
+
+// Close stops replaying and closes the outbox file. Pending entries stay on disk for the next OpenOutbox.
+func (o *Outbox) Close() error {
+	o.mu.Lock()
+	quit, done := o.quit, o.done
+	o.quit = nil
+	o.mu.Unlock()
+
+	if quit != nil {
+		close(quit)
+		<-done
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.file.Close()
+}
+
+// EnableOutbox opens the outbox at path and makes every Processor on this API record errand outcomes there
+// before reporting them. Outcomes that haven't been acknowledged, including ones left over from a previous run,
+// are re-sent every interval until the server receives them. Call e.Outbox.Close() to stop replaying.
+func (e *ErrandsAPI) EnableOutbox(path string, interval time.Duration) error {
+	if interval <= 0 {
+		interval = DefaultOutboxReplayInterval
+	}
+
+	outbox, err := OpenOutbox(path)
+	if err != nil {
+		return err
+	}
+
+	outbox.quit = make(chan struct{})
+	outbox.done = make(chan struct{})
+	e.Outbox = outbox
+
+	go e.replayOutbox(outbox, interval)
+	return nil
+}
+
+func (e *ErrandsAPI) replayOutbox(outbox *Outbox, interval time.Duration) {
+	defer close(outbox.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		e.ReplayOutbox(outbox)
+
+		select {
+		case <-ticker.C:
+		case <-outbox.quit:
+			return
+		}
+	}
+}
+
+// ReplayOutbox re-sends every pending outcome in outbox once, acknowledging the ones the server accepted.
+// Outcomes a processor is still reporting are skipped, so they aren't sent twice. Outcomes older than the outbox's
+// max age, and outcomes for errands the server no longer has, are dropped instead. The outbox file is then compacted
+// if any outcomes have been acknowledged or dropped since it last was.
+func (e *ErrandsAPI) ReplayOutbox(outbox *Outbox) {
+	for _, entry := range outbox.Pending() {
+		if !outbox.begin(entry.ErrandID) {
+			continue
+		}
+
+		e.replayOutcome(outbox, entry)
+		outbox.end(entry.ErrandID)
+	}
+
+	if err := outbox.compact(); err != nil {
+		fmt.Println("Error compacting outbox:", err)
+	}
+}
+
+func (e *ErrandsAPI) replayOutcome(outbox *Outbox, entry *OutboxEntry) {
+	if outbox.expired(entry) {
+		fmt.Println("Dropping outcome older than the outbox max age:", entry.ErrandID)
+		if err := outbox.Ack(entry.ErrandID); err != nil {
+			fmt.Println("Error acknowledging outcome:", entry.ErrandID, "Err:", err)
+		}
+		return
+	}
+
+	err := e.sendOutcome(entry)
+	if errors.Is(err, errOutcomeRejected) && e.errandGone(entry.ErrandID) {
+		// The server answers outcomes for errands it doesn't have with a 500, which would otherwise be retried forever.
+		fmt.Println("Dropping outcome for errand the server doesn't have:", entry.ErrandID, "Err:", err)
+	} else if err != nil {
+		fmt.Println("Error replaying outcome:", entry.ErrandID, "Err:", err)
+		return
+	}
+
+	if err := outbox.Ack(entry.ErrandID); err != nil {
+		fmt.Println("Error acknowledging outcome:", entry.ErrandID, "Err:", err)
+	}
+}
+
+// errandGone reports whether the server has confirmed it has no errand with errandID.
+func (e *ErrandsAPI) errandGone(errandID string) bool {
+	_, err := e.GetErrand(context.Background(), errandID)
+	return errors.Is(err, ErrErrandNotFound)
+}
+
+// reportOutcome sends an outcome to the server, going through the outbox if one is enabled.
+func (e *ErrandsAPI) reportOutcome(entry *OutboxEntry) {
+	if e.Outbox != nil {
+		if err := e.Outbox.record(entry, true); err != nil {
+			fmt.Println("Error recording outcome:", entry.ErrandID, "Err:", err)
+		}
+		defer e.Outbox.end(entry.ErrandID)
+	}
+
+	if err := e.sendOutcome(entry); err != nil {
+		fmt.Println("Error reporting outcome:", entry.ErrandID, "Err:", err)
+		return
+	}
+
+	if e.Outbox != nil {
+		if err := e.Outbox.Ack(entry.ErrandID); err != nil {
+			fmt.Println("Error acknowledging outcome:", entry.ErrandID, "Err:", err)
+		}
+	}
+}
+
+// sendOutcome reports an outcome to the server. It returns an error unless the server accepted it with an OK status,
+// since error responses, like a 500, still have JSON bodies.
+func (e *ErrandsAPI) sendOutcome(entry *OutboxEntry) error {
+	var (
+		res *ErrandResponse
+		err error
+	)
+	if entry.Failed {
+		res, err = e.FailErrand(entry.ErrandID, entry.Reason)
+	} else {
+		res, err = e.CompleteErrand(entry.ErrandID, entry.Results)
+	}
+	if err != nil {
+		return err
+	}
+
+	if res.Status != "OK" {
+		return fmt.Errorf("%w: response status %q", errOutcomeRejected, res.Status)
+	}
+
+	return nil
+}
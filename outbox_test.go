@@ -0,0 +1,223 @@
+package errands
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/polygon-io/errands-server/schemas"
+)
+
+func TestOutboxReplaysUnackedEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox")
+
+	outbox, err := OpenOutbox(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := outbox.Record(&OutboxEntry{ErrandID: "a", Results: map[string]interface{}{"ok": true}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := outbox.Record(&OutboxEntry{ErrandID: "b", Failed: true, Reason: "boom"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := outbox.Ack("a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := outbox.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := OpenOutbox(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reopened.Close()
+
+	pending := reopened.Pending()
+	if len(pending) != 1 {
+		t.Fatalf("expected 1 pending entry, got %d", len(pending))
+	}
+	if pending[0].ErrandID != "b" || !pending[0].Failed || pending[0].Reason != "boom" {
+		t.Errorf("unexpected pending entry: %+v", pending[0])
+	}
+}
+
+func TestReplayOutboxAcksAcceptedOutcomes(t *testing.T) {
+	outbox, err := OpenOutbox(filepath.Join(t.TempDir(), "outbox"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer outbox.Close()
+
+	fake := &fakeServer{errands: []schemas.Errand{
+		{ID: "e1", Status: schemas.StatusActive},
+		{ID: "e2", Status: schemas.StatusActive},
+	}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	api := New(server.URL)
+
+	// The server answers outcomes for errands it doesn't have with a 500 and a JSON body. The errand is gone,
+	// so the outcome is dropped rather than replayed forever.
+	if err := outbox.Record(&OutboxEntry{ErrandID: "missing", Failed: true, Reason: "boom"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := outbox.Record(&OutboxEntry{ErrandID: "e1", Failed: true, Reason: "boom"}); err != nil {
+		t.Fatal(err)
+	}
+	// Failing without a reason is rejected, but the errand still exists, so the outcome stays pending.
+	if err := outbox.Record(&OutboxEntry{ErrandID: "e2", Failed: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	api.ReplayOutbox(outbox)
+
+	pending := outbox.Pending()
+	if len(pending) != 1 || pending[0].ErrandID != "e2" {
+		t.Fatalf("expected only the rejected outcome for an errand that exists to be pending, got %+v", pending)
+	}
+	if status := fake.find("/v1/errand/e1", "").Status; status != schemas.StatusFailed {
+		t.Errorf("expected e1 to be failed, got %s", status)
+	}
+}
+
+func TestReplayOutboxDropsExpiredOutcomes(t *testing.T) {
+	outbox, err := OpenOutbox(filepath.Join(t.TempDir(), "outbox"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer outbox.Close()
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	api := New(server.URL)
+	outbox.SetMaxAge(time.Hour)
+
+	old := time.Now().Add(-2 * time.Hour).UnixMilli()
+	if err := outbox.Record(&OutboxEntry{ErrandID: "old", Failed: true, Reason: "boom", Recorded: old}); err != nil {
+		t.Fatal(err)
+	}
+	if err := outbox.Record(&OutboxEntry{ErrandID: "new", Failed: true, Reason: "boom"}); err != nil {
+		t.Fatal(err)
+	}
+
+	api.ReplayOutbox(outbox)
+
+	pending := outbox.Pending()
+	if len(pending) != 1 || pending[0].ErrandID != "new" {
+		t.Fatalf("expected only the recent outcome to be pending, got %+v", pending)
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("expected only the recent outcome to be sent, got %d requests", n)
+	}
+}
+
+func TestReplayOutboxCompacts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox")
+	outbox, err := OpenOutbox(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/v1/errand/b/") {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"status":"OK"}`))
+	}))
+	defer server.Close()
+
+	api := New(server.URL)
+
+	for _, id := range []string{"a", "b", "c"} {
+		if err := outbox.Record(&OutboxEntry{ErrandID: id, Failed: true, Reason: "boom"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	api.ReplayOutbox(outbox)
+
+	// The acknowledged outcomes are compacted away, leaving just the line for the one still pending.
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(string(b)), "\n"); len(lines) != 1 || !strings.Contains(lines[0], `"errandId":"b"`) {
+		t.Fatalf("expected the outbox file to hold only the pending outcome, got:\n%s", b)
+	}
+
+	// Outcomes recorded after compacting are appended to the new file.
+	if err := outbox.Record(&OutboxEntry{ErrandID: "d", Failed: true, Reason: "boom"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := outbox.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := OpenOutbox(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reopened.Close()
+
+	var ids []string
+	for _, entry := range reopened.Pending() {
+		ids = append(ids, entry.ErrandID)
+	}
+	sort.Strings(ids)
+	if !reflect.DeepEqual(ids, []string{"b", "d"}) {
+		t.Errorf("expected b and d to be pending, got %v", ids)
+	}
+}
+
+func TestReplayOutboxSkipsOutcomesInFlight(t *testing.T) {
+	outbox, err := OpenOutbox(filepath.Join(t.TempDir(), "outbox"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer outbox.Close()
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Write([]byte(`{"status":"OK"}`))
+	}))
+	defer server.Close()
+
+	api := New(server.URL)
+
+	// A processor reporting this outcome has recorded it but not yet heard back from the server.
+	if err := outbox.record(&OutboxEntry{ErrandID: "a", Failed: true, Reason: "boom"}, true); err != nil {
+		t.Fatal(err)
+	}
+
+	api.ReplayOutbox(outbox)
+	if n := atomic.LoadInt32(&requests); n != 0 {
+		t.Fatalf("expected an outcome in flight not to be replayed, got %d requests", n)
+	}
+
+	outbox.end("a")
+	api.ReplayOutbox(outbox)
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Fatalf("expected the outcome to be replayed once it's no longer in flight, got %d requests", n)
+	}
+	if pending := outbox.Pending(); len(pending) != 0 {
+		t.Errorf("expected no pending outcomes, got %+v", pending)
+	}
+}
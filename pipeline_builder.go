@@ -0,0 +1,192 @@
+package errands
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/polygon-io/errands-server/schemas"
+)
+
+// CycleError is returned by PipelineBuilder.Build when the dependencies between errands form a cycle.
+type CycleError struct {
+	// Path lists the errand names around the cycle, starting and ending with the same errand.
+	// Each errand in the path depends on the one after it.
+	Path []string
+}
+
+func (e *CycleError) Error() string {
+	return "dependency cycle: " + strings.Join(e.Path, " -> ")
+}
+
+// PipelineBuilder builds a schemas.Pipeline from errands and the dependencies between them,
+// validating the dependency graph before it's sent to the server.
+type PipelineBuilder struct {
+	name              string
+	deleteOnCompleted bool
+
+	errands []*schemas.Errand
+	// dependencies maps an errand name to the names of the errands it depends on, in the order they were added.
+	dependencies map[string][]string
+	// targets lists the keys of dependencies in the order they were added.
+	targets []string
+}
+
+// NewPipelineBuilder creates a builder for a pipeline with the given name.
+func NewPipelineBuilder(name string) *PipelineBuilder {
+	return &PipelineBuilder{
+		name:         name,
+		dependencies: make(map[string][]string),
+	}
+}
+
+// DeleteOnCompleted sets whether the server should delete the pipeline once it completes.
+func (b *PipelineBuilder) DeleteOnCompleted(deleteOnCompleted bool) *PipelineBuilder {
+	b.deleteOnCompleted = deleteOnCompleted
+	return b
+}
+
+// AddErrand adds an errand to the pipeline. Errands are referred to by name in DependsOn.
+func (b *PipelineBuilder) AddErrand(errand *schemas.Errand) *PipelineBuilder {
+	b.errands = append(b.errands, errand)
+	return b
+}
+
+// DependsOn makes the errand named target wait for each of the errands named in dependencies to complete.
+func (b *PipelineBuilder) DependsOn(target string, dependencies ...string) *PipelineBuilder {
+	if _, exists := b.dependencies[target]; !exists {
+		b.targets = append(b.targets, target)
+	}
+
+	for _, dependency := range dependencies {
+		if !containsString(b.dependencies[target], dependency) {
+			b.dependencies[target] = append(b.dependencies[target], dependency)
+		}
+	}
+
+	return b
+}
+
+// Build validates the pipeline and returns it ready for CreatePipeline. It checks that errands have unique,
+// non-empty names and a type, that dependencies only reference errands in the pipeline and that there are no cycles.
+func (b *PipelineBuilder) Build() (*schemas.Pipeline, error) {
+	if b.name == "" {
+		return nil, errors.New("pipeline name is required")
+	}
+
+	if len(b.errands) == 0 {
+		return nil, errors.New("pipeline has no errands")
+	}
+
+	names := make(map[string]bool, len(b.errands))
+	for i, errand := range b.errands {
+		if errand.Name == "" {
+			return nil, fmt.Errorf("errand %d has no name", i)
+		}
+
+		if errand.Type == "" {
+			return nil, fmt.Errorf("errand %s has no type", errand.Name)
+		}
+
+		if names[errand.Name] {
+			return nil, fmt.Errorf("duplicate errand name: %s", errand.Name)
+		}
+
+		names[errand.Name] = true
+	}
+
+	pipeline := &schemas.Pipeline{
+		Name:              b.name,
+		DeleteOnCompleted: b.deleteOnCompleted,
+		Errands:           b.errands,
+	}
+
+	// Walk errands in the order they were added so errors and the dependency list are deterministic.
+	for _, errand := range b.errands {
+		for _, dependency := range b.dependencies[errand.Name] {
+			if dependency == errand.Name {
+				return nil, fmt.Errorf("errand cannot depend on itself: %s", errand.Name)
+			}
+
+			if !names[dependency] {
+				return nil, fmt.Errorf("errand %s depends on unknown errand: %s", errand.Name, dependency)
+			}
+
+			pipeline.Dependencies = append(pipeline.Dependencies, &schemas.PipelineDependency{
+				Target:    errand.Name,
+				DependsOn: dependency,
+			})
+		}
+	}
+
+	for _, target := range b.targets {
+		if !names[target] {
+			return nil, fmt.Errorf("dependency references unknown errand: %s", target)
+		}
+	}
+
+	if cycle := b.findCycle(); cycle != nil {
+		return nil, cycle
+	}
+
+	return pipeline, nil
+}
+
+// findCycle does a depth first search over the dependency graph and returns the first cycle it finds, if any.
+func (b *PipelineBuilder) findCycle() *CycleError {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make(map[string]int, len(b.errands))
+	var stack []string
+
+	var visit func(name string) *CycleError
+	visit = func(name string) *CycleError {
+		state[name] = visiting
+		stack = append(stack, name)
+
+		for _, dependency := range b.dependencies[name] {
+			switch state[dependency] {
+			case visiting:
+				// The cycle is everything on the stack from the first visit of dependency.
+				for i, n := range stack {
+					if n == dependency {
+						path := append(append([]string{}, stack[i:]...), dependency)
+						return &CycleError{Path: path}
+					}
+				}
+			case unvisited:
+				if cycle := visit(dependency); cycle != nil {
+					return cycle
+				}
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[name] = visited
+		return nil
+	}
+
+	for _, errand := range b.errands {
+		if state[errand.Name] == unvisited {
+			if cycle := visit(errand.Name); cycle != nil {
+				return cycle
+			}
+		}
+	}
+
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
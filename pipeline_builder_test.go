@@ -0,0 +1,91 @@
+package errands
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/polygon-io/errands-server/schemas"
+)
+
+func testErrand(name string) *schemas.Errand {
+	return &schemas.Errand{Name: name, Type: "tester"}
+}
+
+func TestPipelineBuilder(t *testing.T) {
+	pipeline, err := NewPipelineBuilder("etl").
+		AddErrand(testErrand("extract")).
+		AddErrand(testErrand("transform")).
+		AddErrand(testErrand("load")).
+		DependsOn("transform", "extract").
+		DependsOn("load", "transform", "extract", "transform").
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []*schemas.PipelineDependency{
+		{Target: "transform", DependsOn: "extract"},
+		{Target: "load", DependsOn: "transform"},
+		{Target: "load", DependsOn: "extract"},
+	}
+	if !reflect.DeepEqual(pipeline.Dependencies, expected) {
+		t.Errorf("unexpected dependencies: %+v", pipeline.Dependencies)
+	}
+}
+
+func TestPipelineBuilderValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *PipelineBuilder
+		err     string
+	}{
+		{
+			name:    "no errands",
+			builder: NewPipelineBuilder("p"),
+			err:     "pipeline has no errands",
+		},
+		{
+			name:    "duplicate name",
+			builder: NewPipelineBuilder("p").AddErrand(testErrand("a")).AddErrand(testErrand("a")),
+			err:     "duplicate errand name: a",
+		},
+		{
+			name:    "unknown dependency",
+			builder: NewPipelineBuilder("p").AddErrand(testErrand("a")).DependsOn("a", "b"),
+			err:     "errand a depends on unknown errand: b",
+		},
+		{
+			name:    "unknown target",
+			builder: NewPipelineBuilder("p").AddErrand(testErrand("a")).DependsOn("b", "a"),
+			err:     "dependency references unknown errand: b",
+		},
+		{
+			name:    "self dependency",
+			builder: NewPipelineBuilder("p").AddErrand(testErrand("a")).DependsOn("a", "a"),
+			err:     "errand cannot depend on itself: a",
+		},
+		{
+			name: "cycle",
+			builder: NewPipelineBuilder("p").
+				AddErrand(testErrand("a")).AddErrand(testErrand("b")).AddErrand(testErrand("c")).AddErrand(testErrand("d")).
+				DependsOn("a", "b").DependsOn("b", "c").DependsOn("c", "d", "b"),
+			err: "dependency cycle: b -> c -> b",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := test.builder.Build()
+			if err == nil || err.Error() != test.err {
+				t.Errorf("expected error %q, got %v", test.err, err)
+			}
+		})
+	}
+
+	_, err := tests[len(tests)-1].builder.Build()
+	var cycle *CycleError
+	if !errors.As(err, &cycle) || !reflect.DeepEqual(cycle.Path, []string{"b", "c", "b"}) {
+		t.Errorf("expected a *CycleError with the cycle path, got %v", err)
+	}
+}
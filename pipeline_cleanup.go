@@ -0,0 +1,133 @@
+package errands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/polygon-io/errands-server/schemas"
+)
+
+// DefaultCleanupConcurrency is the default number of pipelines CleanupPipelines deletes at once.
+const DefaultCleanupConcurrency = 4
+
+// CleanupOptions configures CleanupPipelines.
+type CleanupOptions struct {
+	// OlderThan is the retention period. Pipelines that ended longer ago than this are deleted.
+	// Pipelines that haven't ended, like failed ones, are aged by when they were created.
+	OlderThan time.Duration
+
+	// Statuses are the pipeline statuses to clean up, which can only be completed or failed. Pipelines that haven't
+	// ended are aged by when they were created, so cleaning up active ones would delete pipelines that are still
+	// running. Defaults to completed pipelines only.
+	Statuses []schemas.Status
+
+	// DryRun reports the pipelines that would be deleted without deleting them.
+	DryRun bool
+
+	// Concurrency limits the number of DeletePipeline requests in flight at once. Defaults to DefaultCleanupConcurrency.
+	Concurrency int
+}
+
+// CleanupSummary reports what CleanupPipelines did.
+type CleanupSummary struct {
+	// Matched are the pipelines older than the retention period, which were deleted unless this was a dry run.
+	Matched []*schemas.Pipeline
+
+	// Deleted are the IDs of the pipelines that were deleted.
+	Deleted []string
+
+	// Failed maps the IDs of pipelines that couldn't be deleted to the reason why.
+	Failed map[string]error
+
+	DryRun bool
+}
+
+// CleanupPipelines deletes pipelines with the given statuses that are older than the retention period.
+// An error is only returned if the pipelines couldn't be listed; failures to delete individual pipelines are
+// reported in the summary.
+func (e *ErrandsAPI) CleanupPipelines(ctx context.Context, opts CleanupOptions) (*CleanupSummary, error) {
+	if opts.OlderThan <= 0 {
+		return nil, errors.New("cleanup pipelines: retention period must be positive")
+	}
+
+	if len(opts.Statuses) == 0 {
+		opts.Statuses = []schemas.Status{schemas.StatusCompleted}
+	}
+
+	for _, status := range opts.Statuses {
+		if status != schemas.StatusCompleted && status != schemas.StatusFailed {
+			return nil, fmt.Errorf("cleanup pipelines: can't clean up %q pipelines; statuses must be completed or failed", status)
+		}
+	}
+
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultCleanupConcurrency
+	}
+
+	cutoff := time.Now().Add(-opts.OlderThan)
+	summary := &CleanupSummary{
+		Failed: make(map[string]error),
+		DryRun: opts.DryRun,
+	}
+
+	for _, status := range opts.Statuses {
+		// A pipeline can't have ended before it was created, so only pipelines created before the cutoff can match.
+		pipelines, err := e.ListPipelinesFiltered(ctx, ListPipelinesOptions{Status: status, CreatedBefore: cutoff})
+		if err != nil {
+			return nil, fmt.Errorf("cleanup pipelines: %w", err)
+		}
+
+		for _, pipeline := range pipelines {
+			if pipelineEnded(pipeline).Before(cutoff) {
+				summary.Matched = append(summary.Matched, pipeline)
+			}
+		}
+	}
+
+	if opts.DryRun {
+		return summary, nil
+	}
+
+	var mu sync.Mutex
+	attempted := make([]bool, len(summary.Matched))
+	forEach(ctx, len(summary.Matched), opts.Concurrency, func(i int) {
+		id := summary.Matched[i].ID
+
+		res, err := e.DeletePipeline(ctx, id)
+		if err == nil && res.Status != "OK" && res.Status != "not_found" {
+			err = fmt.Errorf("response status %q", res.Status)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		attempted[i] = true
+		if err != nil {
+			summary.Failed[id] = err
+			return
+		}
+
+		summary.Deleted = append(summary.Deleted, id)
+	})
+
+	// Pipelines that weren't attempted because ctx was canceled count as failures too.
+	for i, pipeline := range summary.Matched {
+		if !attempted[i] {
+			summary.Failed[pipeline.ID] = ctx.Err()
+		}
+	}
+
+	return summary, nil
+}
+
+// pipelineEnded returns when the pipeline ended, or when it was created if it hasn't ended.
+func pipelineEnded(pipeline *schemas.Pipeline) time.Time {
+	if pipeline.EndedMillis != 0 {
+		return time.UnixMilli(pipeline.EndedMillis)
+	}
+
+	return time.UnixMilli(pipeline.StartedMillis)
+}
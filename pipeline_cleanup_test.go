@@ -0,0 +1,112 @@
+package errands
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/polygon-io/errands-server/schemas"
+)
+
+func TestCleanupPipelines(t *testing.T) {
+	now := time.Now()
+	old := now.Add(-48 * time.Hour).UnixMilli()
+	recent := now.Add(-time.Hour).UnixMilli()
+
+	var (
+		mu      sync.Mutex
+		deletes []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/pipelines/":
+			switch r.URL.Query().Get("status") {
+			case "completed":
+				fmt.Fprintf(w, `{"status":"OK","results":[
+					{"id":"p1","name":"a","status":"completed","startedMillis":%d,"endedMillis":%d},
+					{"id":"p2","name":"b","status":"completed","startedMillis":%d,"endedMillis":%d},
+					{"id":"p3","name":"c","status":"completed","startedMillis":%d,"endedMillis":%d}]}`,
+					old, old, old, recent, recent, recent)
+			case "failed":
+				fmt.Fprintf(w, `{"status":"OK","results":[{"id":"p4","name":"d","status":"failed","startedMillis":%d}]}`, old)
+			default:
+				t.Errorf("unexpected query: %s", r.URL.RawQuery)
+			}
+		case r.Method == http.MethodDelete:
+			mu.Lock()
+			deletes = append(deletes, r.URL.Path)
+			mu.Unlock()
+
+			if r.URL.Path == "/v1/pipeline/p4" {
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte(`{"message":"Internal Server Error!"}`))
+				return
+			}
+			w.Write([]byte(`{"status":"OK"}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	api := New(server.URL)
+	opts := CleanupOptions{
+		OlderThan: 24 * time.Hour,
+		Statuses:  []schemas.Status{schemas.StatusCompleted, schemas.StatusFailed},
+		DryRun:    true,
+	}
+
+	summary, err := api.CleanupPipelines(context.Background(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(summary.Matched) != 2 || summary.Matched[0].ID != "p1" || summary.Matched[1].ID != "p4" {
+		t.Errorf("unexpected matches: %+v", summary.Matched)
+	}
+	if len(deletes) != 0 || len(summary.Deleted) != 0 {
+		t.Errorf("dry run deleted pipelines: %v", deletes)
+	}
+
+	opts.DryRun = false
+	summary, err = api.CleanupPipelines(context.Background(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sort.Strings(deletes)
+	if len(deletes) != 2 || deletes[0] != "/v1/pipeline/p1" || deletes[1] != "/v1/pipeline/p4" {
+		t.Errorf("unexpected deletes: %v", deletes)
+	}
+
+	if len(summary.Deleted) != 1 || summary.Deleted[0] != "p1" {
+		t.Errorf("unexpected deleted pipelines: %v", summary.Deleted)
+	}
+
+	if len(summary.Failed) != 1 || summary.Failed["p4"] == nil {
+		t.Errorf("unexpected failures: %v", summary.Failed)
+	}
+}
+
+func TestCleanupPipelinesRejectsUnendedStatuses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+	}))
+	defer server.Close()
+
+	api := New(server.URL)
+	for _, status := range []schemas.Status{schemas.StatusActive, schemas.StatusBlocked, schemas.StatusInactive} {
+		_, err := api.CleanupPipelines(context.Background(), CleanupOptions{
+			OlderThan: 24 * time.Hour,
+			Statuses:  []schemas.Status{schemas.StatusCompleted, status},
+		})
+		if err == nil {
+			t.Errorf("expected cleaning up %s pipelines to fail", status)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package errands
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/polygon-io/errands-server/schemas"
+)
+
+// Defaults for ListPipelinesOptions.
+const (
+	DefaultPipelinePageSize   = 100
+	DefaultHydrateConcurrency = 4
+)
+
+// ListPipelinesOptions filters and pages the pipelines returned by IteratePipelines and ListPipelinesFiltered.
+type ListPipelinesOptions struct {
+	// Status only includes pipelines with this status. It's filtered on the server.
+	Status schemas.Status
+
+	// NamePrefix only includes pipelines whose name starts with this prefix.
+	NamePrefix string
+
+	// CreatedAfter and CreatedBefore only include pipelines created in this window. Zero values are unbounded.
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+
+	// PageSize is the number of pipelines in each page. Defaults to DefaultPipelinePageSize.
+	PageSize int
+
+	// Hydrate fetches each listed pipeline with GetPipeline so its errands and dependencies are populated.
+	Hydrate bool
+
+	// HydrateConcurrency limits the number of GetPipeline requests in flight at once. Defaults to DefaultHydrateConcurrency.
+	HydrateConcurrency int
+}
+
+func (o ListPipelinesOptions) matches(pipeline *schemas.Pipeline) bool {
+	if !strings.HasPrefix(pipeline.Name, o.NamePrefix) {
+		return false
+	}
+
+	created := time.UnixMilli(pipeline.StartedMillis)
+	if !o.CreatedAfter.IsZero() && !created.After(o.CreatedAfter) {
+		return false
+	}
+
+	if !o.CreatedBefore.IsZero() && !created.Before(o.CreatedBefore) {
+		return false
+	}
+
+	return true
+}
+
+// PipelineIterator pages through pipelines, newest first. Create one with IteratePipelines and use it like:
+//
+//	it := api.IteratePipelines(opts)
+//	for it.Next(ctx) {
+//		for _, pipeline := range it.Page() {
+//			...
+//		}
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type PipelineIterator struct {
+	api  *ErrandsAPI
+	opts ListPipelinesOptions
+
+	listed    bool
+	pipelines []*schemas.Pipeline
+	page      []*schemas.Pipeline
+	err       error
+}
+
+// IteratePipelines returns an iterator over the pipelines matching opts.
+// The server returns every pipeline in one response, so filtering by anything but status and paging happen
+// on the client. Paging still bounds hydration: each page is hydrated only when Next reaches it.
+func (e *ErrandsAPI) IteratePipelines(opts ListPipelinesOptions) *PipelineIterator {
+	if opts.PageSize <= 0 {
+		opts.PageSize = DefaultPipelinePageSize
+	}
+
+	if opts.HydrateConcurrency <= 0 {
+		opts.HydrateConcurrency = DefaultHydrateConcurrency
+	}
+
+	return &PipelineIterator{api: e, opts: opts}
+}
+
+// Next advances to the next page, returning false when there are no more pages or an error occurred.
+func (it *PipelineIterator) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+
+	if !it.listed {
+		if err := it.list(ctx); err != nil {
+			it.err = err
+			return false
+		}
+		it.listed = true
+	}
+
+	if len(it.pipelines) == 0 {
+		it.page = nil
+		return false
+	}
+
+	n := it.opts.PageSize
+	if n > len(it.pipelines) {
+		n = len(it.pipelines)
+	}
+
+	it.page, it.pipelines = it.pipelines[:n], it.pipelines[n:]
+
+	if it.opts.Hydrate {
+		page, err := it.api.hydratePipelines(ctx, it.page, it.opts.HydrateConcurrency)
+		if err != nil {
+			it.err = err
+			it.page = nil
+			return false
+		}
+		it.page = page
+	}
+
+	return true
+}
+
+// Page returns the pipelines in the current page.
+func (it *PipelineIterator) Page() []*schemas.Pipeline {
+	return it.page
+}
+
+// Err returns the error that stopped iteration, if any.
+func (it *PipelineIterator) Err() error {
+	return it.err
+}
+
+func (it *PipelineIterator) list(ctx context.Context) error {
+	res, err := it.api.ListPipelines(ctx, string(it.opts.Status))
+	if err != nil {
+		return fmt.Errorf("list pipelines: %w", err)
+	}
+
+	if res.Status != "OK" {
+		return fmt.Errorf("list pipelines: response status %q", res.Status)
+	}
+
+	for _, pipeline := range res.Results {
+		if it.opts.matches(pipeline) {
+			it.pipelines = append(it.pipelines, pipeline)
+		}
+	}
+
+	sort.Slice(it.pipelines, func(i, j int) bool {
+		if it.pipelines[i].StartedMillis != it.pipelines[j].StartedMillis {
+			return it.pipelines[i].StartedMillis > it.pipelines[j].StartedMillis
+		}
+		return it.pipelines[i].ID < it.pipelines[j].ID
+	})
+
+	return nil
+}
+
+// ListPipelinesFiltered returns every pipeline matching opts, newest first. See IteratePipelines.
+func (e *ErrandsAPI) ListPipelinesFiltered(ctx context.Context, opts ListPipelinesOptions) ([]*schemas.Pipeline, error) {
+	var pipelines []*schemas.Pipeline
+
+	it := e.IteratePipelines(opts)
+	for it.Next(ctx) {
+		pipelines = append(pipelines, it.Page()...)
+	}
+
+	return pipelines, it.Err()
+}
+
+// hydratePipelines fetches the full details of each pipeline, with at most concurrency requests in flight.
+// Pipelines that were deleted since they were listed are dropped.
+func (e *ErrandsAPI) hydratePipelines(ctx context.Context, pipelines []*schemas.Pipeline, concurrency int) ([]*schemas.Pipeline, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		errOnce  sync.Once
+		firstErr error
+	)
+
+	hydrated := make([]*schemas.Pipeline, len(pipelines))
+	forEach(ctx, len(pipelines), concurrency, func(i int) {
+		res, err := e.GetPipeline(ctx, pipelines[i].ID)
+		if err != nil {
+			errOnce.Do(func() {
+				firstErr = fmt.Errorf("get pipeline %s: %w", pipelines[i].ID, err)
+				cancel()
+			})
+			return
+		}
+
+		if res.Status == "OK" {
+			hydrated[i] = &res.Results
+		}
+	})
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	page := hydrated[:0]
+	for _, pipeline := range hydrated {
+		if pipeline != nil {
+			page = append(page, pipeline)
+		}
+	}
+
+	return page, nil
+}
@@ -0,0 +1,82 @@
+package errands
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestIteratePipelines(t *testing.T) {
+	var gets, inFlight, maxInFlight int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/pipelines/":
+			if r.URL.Query().Get("status") != "completed" {
+				t.Errorf("unexpected query: %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"status":"OK","results":[
+				{"id":"p1","name":"etl-a","status":"completed","startedMillis":1000},
+				{"id":"p2","name":"etl-b","status":"completed","startedMillis":4000},
+				{"id":"p3","name":"other","status":"completed","startedMillis":3000},
+				{"id":"p4","name":"etl-c","status":"completed","startedMillis":3000},
+				{"id":"p5","name":"etl-d","status":"completed","startedMillis":2000},
+				{"id":"p6","name":"etl-e","status":"completed","startedMillis":9000}]}`))
+		default:
+			atomic.AddInt32(&gets, 1)
+			n := atomic.AddInt32(&inFlight, 1)
+			defer atomic.AddInt32(&inFlight, -1)
+			for {
+				max := atomic.LoadInt32(&maxInFlight)
+				if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+
+			if r.URL.Path == "/v1/pipeline/p5" {
+				w.Write([]byte(`{"status":"not_found"}`))
+				return
+			}
+			id := r.URL.Path[len("/v1/pipeline/"):]
+			w.Write([]byte(`{"status":"OK","results":{"id":"` + id + `","errands":[{"id":"e1"}]}}`))
+		}
+	}))
+	defer server.Close()
+
+	it := New(server.URL).IteratePipelines(ListPipelinesOptions{
+		Status:             "completed",
+		NamePrefix:         "etl-",
+		CreatedAfter:       time.UnixMilli(1000),
+		CreatedBefore:      time.UnixMilli(9000),
+		PageSize:           2,
+		Hydrate:            true,
+		HydrateConcurrency: 1,
+	})
+
+	var pages [][]string
+	for it.Next(context.Background()) {
+		var ids []string
+		for _, pipeline := range it.Page() {
+			if len(pipeline.Errands) != 1 {
+				t.Errorf("pipeline %s wasn't hydrated", pipeline.ID)
+			}
+			ids = append(ids, pipeline.ID)
+		}
+		pages = append(pages, ids)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	// p5 was deleted between listing and hydrating, so it's dropped from its page.
+	if len(pages) != 2 || len(pages[0]) != 2 || pages[0][0] != "p2" || pages[0][1] != "p4" || len(pages[1]) != 0 {
+		t.Errorf("unexpected pages: %v", pages)
+	}
+
+	if gets != 3 || maxInFlight != 1 {
+		t.Errorf("expected 3 sequential hydrations, got %d with up to %d in flight", gets, maxInFlight)
+	}
+}
@@ -0,0 +1,240 @@
+package errands
+
+import (
+	"time"
+
+	"github.com/polygon-io/errands-server/schemas"
+)
+
+// ProgressOptions configures AnalyzePipeline.
+type ProgressOptions struct {
+	// Estimates are how long errands of each type are expected to take, used for errands that haven't finished.
+	// Without an estimate, an active errand's reported progress is extrapolated, and otherwise the average
+	// duration of completed errands of the same type in the pipeline is used.
+	Estimates map[string]time.Duration
+
+	// Now is the time to measure elapsed time and the ETA from. Defaults to time.Now().
+	Now time.Time
+}
+
+// BlockedErrand is a blocked errand and the names of the errands it's still waiting on.
+type BlockedErrand struct {
+	Errand    *schemas.Errand
+	WaitingOn []string
+}
+
+// PipelineProgress describes how far along a pipeline is and what's holding it up.
+type PipelineProgress struct {
+	Total     int
+	Completed int
+
+	// Percent is the percentage of the pipeline's errands that are done, counting active errands' reported progress.
+	Percent float64
+
+	Running []*schemas.Errand
+	Blocked []*BlockedErrand
+	Failed  []*schemas.Errand
+
+	// CriticalPath is the longest chain of dependent errands, by name, measured with actual durations for finished
+	// errands and estimated durations for the rest. It's what determines how long the pipeline takes.
+	CriticalPath         []string
+	CriticalPathDuration time.Duration
+
+	// Remaining is the estimated time until the pipeline completes, and ETA is when that is.
+	// ETA is zero if it can't be estimated: an unfinished errand has no estimate, or an errand has failed.
+	Remaining time.Duration
+	ETA       time.Time
+}
+
+// AnalyzePipeline computes the progress of a pipeline fetched with GetPipeline.
+func AnalyzePipeline(pipeline *schemas.Pipeline, opts ProgressOptions) *PipelineProgress {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	progress := &PipelineProgress{Total: len(pipeline.Errands)}
+
+	dependencies := make(map[string][]string)
+	for _, dep := range pipeline.Dependencies {
+		dependencies[dep.Target] = append(dependencies[dep.Target], dep.DependsOn)
+	}
+
+	errands := make(map[string]*schemas.Errand, len(pipeline.Errands))
+	for _, errand := range pipeline.Errands {
+		errands[errand.Name] = errand
+	}
+
+	averages := averageDurationsByType(pipeline.Errands)
+	estimate := func(errand *schemas.Errand) (time.Duration, bool) {
+		if d, ok := opts.Estimates[errand.Type]; ok {
+			return d, true
+		}
+		d, ok := averages[errand.Type]
+		return d, ok
+	}
+
+	// total is each errand's actual or estimated duration, and remaining is how much of it is left.
+	total := make(map[string]time.Duration, len(pipeline.Errands))
+	remaining := make(map[string]time.Duration, len(pipeline.Errands))
+	etaKnown := true
+
+	var done float64
+	for _, errand := range pipeline.Errands {
+		switch errand.Status {
+		case schemas.StatusCompleted:
+			progress.Completed++
+			done++
+			total[errand.Name] = errandDuration(errand)
+
+		case schemas.StatusActive:
+			progress.Running = append(progress.Running, errand)
+			done += errand.Progress / 100
+
+			elapsed := now.Sub(time.UnixMilli(errand.Started))
+			d, ok := opts.Estimates[errand.Type]
+			if !ok && errand.Progress > 0 {
+				d, ok = time.Duration(float64(elapsed)*100/errand.Progress), true
+			}
+			if !ok {
+				d, ok = estimate(errand)
+			}
+			if !ok {
+				etaKnown = false
+			}
+			if d < elapsed {
+				d = elapsed
+			}
+			total[errand.Name] = d
+			remaining[errand.Name] = d - elapsed
+
+		default:
+			switch errand.Status {
+			case schemas.StatusFailed:
+				progress.Failed = append(progress.Failed, errand)
+				etaKnown = false
+			case schemas.StatusBlocked:
+				blocked := &BlockedErrand{Errand: errand}
+				for _, dep := range dependencies[errand.Name] {
+					if dependency, exists := errands[dep]; !exists || dependency.Status != schemas.StatusCompleted {
+						blocked.WaitingOn = append(blocked.WaitingOn, dep)
+					}
+				}
+				progress.Blocked = append(progress.Blocked, blocked)
+			}
+
+			d, ok := estimate(errand)
+			if !ok {
+				etaKnown = false
+			}
+			total[errand.Name] = d
+			remaining[errand.Name] = d
+		}
+	}
+
+	if progress.Total > 0 {
+		progress.Percent = done / float64(progress.Total) * 100
+	}
+
+	order, acyclic := topologicalOrder(pipeline.Errands, dependencies)
+	if !acyclic {
+		return progress
+	}
+
+	progress.CriticalPath, progress.CriticalPathDuration = longestPath(order, dependencies, total)
+	_, progress.Remaining = longestPath(order, dependencies, remaining)
+
+	if etaKnown {
+		progress.ETA = now.Add(progress.Remaining)
+	}
+
+	return progress
+}
+
+// averageDurationsByType averages the durations of completed errands by type.
+func averageDurationsByType(errands []*schemas.Errand) map[string]time.Duration {
+	sums := make(map[string]time.Duration)
+	counts := make(map[string]int)
+	for _, errand := range errands {
+		if errand.Status == schemas.StatusCompleted && errand.Started != 0 {
+			sums[errand.Type] += errandDuration(errand)
+			counts[errand.Type]++
+		}
+	}
+
+	averages := make(map[string]time.Duration, len(sums))
+	for errandType, sum := range sums {
+		averages[errandType] = sum / time.Duration(counts[errandType])
+	}
+
+	return averages
+}
+
+// topologicalOrder orders errand names so each comes after its dependencies,
+// returning false if the dependencies have a cycle.
+func topologicalOrder(errands []*schemas.Errand, dependencies map[string][]string) ([]string, bool) {
+	visited := make(map[string]int, len(errands)) // 1 while visiting, 2 once done
+	order := make([]string, 0, len(errands))
+
+	var visit func(name string) bool
+	visit = func(name string) bool {
+		switch visited[name] {
+		case 1:
+			return false
+		case 2:
+			return true
+		}
+
+		visited[name] = 1
+		for _, dep := range dependencies[name] {
+			if !visit(dep) {
+				return false
+			}
+		}
+		visited[name] = 2
+		order = append(order, name)
+
+		return true
+	}
+
+	for _, errand := range errands {
+		if !visit(errand.Name) {
+			return nil, false
+		}
+	}
+
+	return order, true
+}
+
+// longestPath returns the path through the dependency graph with the largest total weight, and that weight.
+func longestPath(order []string, dependencies map[string][]string, weights map[string]time.Duration) ([]string, time.Duration) {
+	finish := make(map[string]time.Duration, len(order))
+	previous := make(map[string]string, len(order))
+
+	var end string
+	for _, name := range order {
+		var start time.Duration
+		for _, dep := range dependencies[name] {
+			if previous[name] == "" || finish[dep] > start {
+				start = finish[dep]
+				previous[name] = dep
+			}
+		}
+
+		finish[name] = start + weights[name]
+		if end == "" || finish[name] > finish[end] {
+			end = name
+		}
+	}
+
+	if end == "" {
+		return nil, 0
+	}
+
+	var path []string
+	for name := end; name != ""; name = previous[name] {
+		path = append([]string{name}, path...)
+	}
+
+	return path, finish[end]
+}
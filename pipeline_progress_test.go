@@ -0,0 +1,67 @@
+package errands
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/polygon-io/errands-server/schemas"
+)
+
+func TestAnalyzePipeline(t *testing.T) {
+	now := time.UnixMilli(100_000)
+
+	// extract (done, 10s) -> transform-0 (active, 20s in, 50%) -> load (blocked)
+	//                     -> transform-1 (done, 5s)            ->
+	// report (inactive), which is on its own.
+	pipeline := &schemas.Pipeline{
+		Errands: []*schemas.Errand{
+			{Name: "extract", Type: "extract", Status: schemas.StatusCompleted, Started: 10_000, Completed: 20_000},
+			{Name: "transform-0", Type: "transform", Status: schemas.StatusActive, Started: 80_000, Progress: 50},
+			{Name: "transform-1", Type: "transform", Status: schemas.StatusCompleted, Started: 20_000, Completed: 25_000},
+			{Name: "load", Type: "load", Status: schemas.StatusBlocked},
+			{Name: "report", Type: "report", Status: schemas.StatusInactive},
+		},
+		Dependencies: []*schemas.PipelineDependency{
+			{Target: "transform-0", DependsOn: "extract"},
+			{Target: "transform-1", DependsOn: "extract"},
+			{Target: "load", DependsOn: "transform-0"},
+			{Target: "load", DependsOn: "transform-1"},
+		},
+	}
+
+	progress := AnalyzePipeline(pipeline, ProgressOptions{
+		Estimates: map[string]time.Duration{"load": 30 * time.Second, "report": time.Minute},
+		Now:       now,
+	})
+
+	if progress.Total != 5 || progress.Completed != 2 || progress.Percent != 50 {
+		t.Errorf("unexpected progress: %d/%d, %v%%", progress.Completed, progress.Total, progress.Percent)
+	}
+
+	if len(progress.Running) != 1 || progress.Running[0].Name != "transform-0" {
+		t.Errorf("unexpected running errands: %+v", progress.Running)
+	}
+
+	if len(progress.Blocked) != 1 || !reflect.DeepEqual(progress.Blocked[0].WaitingOn, []string{"transform-0"}) {
+		t.Errorf("unexpected blocked errands: %+v", progress.Blocked)
+	}
+
+	// transform has no estimate, so transform-0's progress is extrapolated: 50% done in 20s means 40s in total.
+	if expected := []string{"extract", "transform-0", "load"}; !reflect.DeepEqual(progress.CriticalPath, expected) {
+		t.Errorf("expected critical path %v, got %v", expected, progress.CriticalPath)
+	}
+	if progress.CriticalPathDuration != 80*time.Second {
+		t.Errorf("unexpected critical path duration: %s", progress.CriticalPathDuration)
+	}
+
+	// report's minute is longer than the 20s left of transform-0 followed by load's 30s.
+	if progress.Remaining != time.Minute || !progress.ETA.Equal(now.Add(time.Minute)) {
+		t.Errorf("unexpected remaining time %s and ETA %s", progress.Remaining, progress.ETA)
+	}
+
+	pipeline.Errands[4].Type = "unknown"
+	if progress := AnalyzePipeline(pipeline, ProgressOptions{Now: now}); !progress.ETA.IsZero() {
+		t.Errorf("expected no ETA without estimates, got %s", progress.ETA)
+	}
+}
@@ -0,0 +1,151 @@
+package errands
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/polygon-io/errands-server/schemas"
+)
+
+// statusColors are the fill colors used for errands of each status when rendering pipelines.
+var statusColors = map[schemas.Status]string{
+	schemas.StatusBlocked:   "#d9d9d9",
+	schemas.StatusInactive:  "#ffffff",
+	schemas.StatusActive:    "#9ecae1",
+	schemas.StatusFailed:    "#fc9272",
+	schemas.StatusCompleted: "#a1d99b",
+}
+
+// RenderPipelineDOT renders the pipeline's dependency graph in Graphviz DOT format. Each errand is a node colored
+// by its status and labelled with its name, status and duration, with edges pointing from an errand to its dependents.
+// It returns an error if a dependency names an errand that isn't in the pipeline.
+func RenderPipelineDOT(pipeline *schemas.Pipeline) (string, error) {
+	if err := checkDependencies(pipeline); err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "digraph %s {\n", dotQuote(pipeline.Name))
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box, style=\"rounded,filled\", fontname=\"Helvetica\"];\n")
+
+	for _, errand := range pipeline.Errands {
+		fmt.Fprintf(&b, "  %s [label=%s, fillcolor=%s];\n",
+			dotQuote(errand.Name), dotQuote(errandLabel(errand, "\n")), dotQuote(statusColor(errand.Status)))
+	}
+
+	for _, dep := range pipeline.Dependencies {
+		fmt.Fprintf(&b, "  %s -> %s;\n", dotQuote(dep.DependsOn), dotQuote(dep.Target))
+	}
+
+	b.WriteString("}\n")
+	return b.String(), nil
+}
+
+// RenderPipelineMermaid renders the pipeline's dependency graph as a Mermaid flowchart, styled like RenderPipelineDOT.
+// It returns an error if a dependency names an errand that isn't in the pipeline.
+func RenderPipelineMermaid(pipeline *schemas.Pipeline) (string, error) {
+	if err := checkDependencies(pipeline); err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+
+	b.WriteString("graph LR\n")
+
+	// Mermaid node IDs can't contain arbitrary characters, so nodes get generated IDs and the name goes in the label.
+	ids := make(map[string]string, len(pipeline.Errands))
+	for i, errand := range pipeline.Errands {
+		id := fmt.Sprintf("e%d", i)
+		ids[errand.Name] = id
+		fmt.Fprintf(&b, "  %s[\"%s\"]\n", id, mermaidEscape(errandLabel(errand, "<br/>")))
+	}
+
+	for _, dep := range pipeline.Dependencies {
+		fmt.Fprintf(&b, "  %s --> %s\n", ids[dep.DependsOn], ids[dep.Target])
+	}
+
+	for _, status := range schemas.ErrandStatuses {
+		var members []string
+		for _, errand := range pipeline.Errands {
+			if errand.Status == status {
+				members = append(members, ids[errand.Name])
+			}
+		}
+
+		if len(members) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&b, "  classDef %s fill:%s,stroke:#333\n", status, statusColor(status))
+		fmt.Fprintf(&b, "  class %s %s\n", strings.Join(members, ","), status)
+	}
+
+	return b.String(), nil
+}
+
+// checkDependencies makes sure every dependency of the pipeline is between errands in it, like PipelineBuilder does,
+// since a graph can't show an edge to an errand it has no node for.
+func checkDependencies(pipeline *schemas.Pipeline) error {
+	names := make(map[string]bool, len(pipeline.Errands))
+	for _, errand := range pipeline.Errands {
+		names[errand.Name] = true
+	}
+
+	for _, dep := range pipeline.Dependencies {
+		if !names[dep.Target] {
+			return fmt.Errorf("dependency references unknown errand: %s", dep.Target)
+		}
+
+		if !names[dep.DependsOn] {
+			return fmt.Errorf("errand %s depends on unknown errand: %s", dep.Target, dep.DependsOn)
+		}
+	}
+
+	return nil
+}
+
+func errandLabel(errand *schemas.Errand, lineBreak string) string {
+	label := errand.Name + lineBreak + string(errand.Status)
+
+	duration := errandDuration(errand)
+	if errand.Status == schemas.StatusActive && errand.Started > 0 {
+		duration = time.Since(time.UnixMilli(errand.Started))
+	}
+
+	if duration > 0 {
+		label += " (" + formatDuration(duration) + ")"
+	}
+
+	return label
+}
+
+func statusColor(status schemas.Status) string {
+	if color, exists := statusColors[status]; exists {
+		return color
+	}
+
+	return "#ffffff"
+}
+
+// formatDuration rounds d to a precision that's readable in a graph label.
+func formatDuration(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+
+	return d.Round(time.Second).String()
+}
+
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+func mermaidEscape(s string) string {
+	return strings.ReplaceAll(s, `"`, "#quot;")
+}
@@ -0,0 +1,94 @@
+package errands
+
+import (
+	"testing"
+
+	"github.com/polygon-io/errands-server/schemas"
+)
+
+func renderTestPipeline() *schemas.Pipeline {
+	return &schemas.Pipeline{
+		Name: "daily \"etl\"",
+		Errands: []*schemas.Errand{
+			{Name: "extract", Status: schemas.StatusCompleted, Started: 1000, Completed: 4000},
+			{Name: "load", Status: schemas.StatusBlocked},
+		},
+		Dependencies: []*schemas.PipelineDependency{
+			{Target: "load", DependsOn: "extract"},
+		},
+	}
+}
+
+func TestRenderPipelineDOT(t *testing.T) {
+	expected := `digraph "daily \"etl\"" {
+  rankdir=LR;
+  node [shape=box, style="rounded,filled", fontname="Helvetica"];
+  "extract" [label="extract\ncompleted (3s)", fillcolor="#a1d99b"];
+  "load" [label="load\nblocked", fillcolor="#d9d9d9"];
+  "extract" -> "load";
+}
+`
+	actual, err := RenderPipelineDOT(renderTestPipeline())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual != expected {
+		t.Errorf("unexpected DOT output:\n%s", actual)
+	}
+}
+
+func TestRenderPipelineMermaid(t *testing.T) {
+	expected := `graph LR
+  e0["extract<br/>completed (3s)"]
+  e1["load<br/>blocked"]
+  e0 --> e1
+  classDef blocked fill:#d9d9d9,stroke:#333
+  class e1 blocked
+  classDef completed fill:#a1d99b,stroke:#333
+  class e0 completed
+`
+	actual, err := RenderPipelineMermaid(renderTestPipeline())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual != expected {
+		t.Errorf("unexpected Mermaid output:\n%s", actual)
+	}
+}
+
+func TestRenderPipelineUnknownErrands(t *testing.T) {
+	tests := []struct {
+		name string
+		dep  *schemas.PipelineDependency
+		err  string
+	}{
+		{
+			name: "unknown target",
+			dep:  &schemas.PipelineDependency{Target: "transform", DependsOn: "extract"},
+			err:  "dependency references unknown errand: transform",
+		},
+		{
+			name: "unknown dependency",
+			dep:  &schemas.PipelineDependency{Target: "load", DependsOn: "transform"},
+			err:  "errand load depends on unknown errand: transform",
+		},
+	}
+
+	renderers := map[string]func(*schemas.Pipeline) (string, error){
+		"dot":     RenderPipelineDOT,
+		"mermaid": RenderPipelineMermaid,
+	}
+
+	for _, test := range tests {
+		for format, render := range renderers {
+			t.Run(test.name+"/"+format, func(t *testing.T) {
+				pipeline := renderTestPipeline()
+				pipeline.Dependencies = append(pipeline.Dependencies, test.dep)
+
+				if _, err := render(pipeline); err == nil || err.Error() != test.err {
+					t.Errorf("expected error %q, got %v", test.err, err)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,350 @@
+package errands
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/polygon-io/errands-server/schemas"
+	"gopkg.in/yaml.v3"
+)
+
+// SpecError is a problem found in a pipeline spec, along with the line it was found on.
+type SpecError struct {
+	Line    int
+	Message string
+}
+
+func (e SpecError) Error() string {
+	if e.Line == 0 {
+		return e.Message
+	}
+
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// SpecErrors lists every problem found in a pipeline spec.
+type SpecErrors []SpecError
+
+func (e SpecErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+
+	return strings.Join(messages, "\n")
+}
+
+// The keys allowed in each part of a pipeline spec.
+var (
+	pipelineSpecKeys = []string{"name", "deleteOnCompleted", "variables", "errands"}
+	errandSpecKeys   = []string{"name", "type", "data", "options", "dependsOn"}
+	optionsSpecKeys  = []string{"ttl", "retries", "priority", "deleteOnCompleted"}
+)
+
+type pipelineSpec struct {
+	Name              string            `yaml:"name"`
+	DeleteOnCompleted bool              `yaml:"deleteOnCompleted"`
+	Variables         map[string]string `yaml:"variables"`
+	Errands           []errandSpec      `yaml:"errands"`
+}
+
+type errandSpec struct {
+	Name    string                 `yaml:"name"`
+	Type    string                 `yaml:"type"`
+	Data    map[string]interface{} `yaml:"data"`
+	Options struct {
+		TTL               int  `yaml:"ttl"`
+		Retries           int  `yaml:"retries"`
+		Priority          int  `yaml:"priority"`
+		DeleteOnCompleted bool `yaml:"deleteOnCompleted"`
+	} `yaml:"options"`
+	DependsOn []string `yaml:"dependsOn"`
+
+	line int
+}
+
+func (s *errandSpec) UnmarshalYAML(node *yaml.Node) error {
+	type plain errandSpec
+	if err := node.Decode((*plain)(s)); err != nil {
+		return err
+	}
+
+	s.line = node.Line
+	return nil
+}
+
+// LoadPipelineSpec reads a pipeline spec from a YAML or JSON file. See ParsePipelineSpec.
+func LoadPipelineSpec(path string, vars map[string]string) (*schemas.Pipeline, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read pipeline spec: %w", err)
+	}
+
+	pipeline, err := ParsePipelineSpec(data, vars)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return pipeline, nil
+}
+
+// ParsePipelineSpec parses a YAML or JSON pipeline spec into a pipeline ready for CreatePipeline. A spec looks like:
+//
+//	name: daily-etl-${date}
+//	deleteOnCompleted: true
+//	variables:
+//	  date: "2022-01-01" # default, overridden by vars
+//	errands:
+//	  - name: extract
+//	    type: extract
+//	    data: {date: "${date}"}
+//	    options: {ttl: 600, retries: 2, priority: 1, deleteOnCompleted: false}
+//	  - name: load
+//	    type: load
+//	    dependsOn: [extract]
+//
+// ${variable} references in the pipeline name, errand names and string data values are replaced with vars,
+// falling back to the spec's variables. Problems are returned as SpecErrors with the line they were found on.
+func ParsePipelineSpec(data []byte, vars map[string]string) (*schemas.Pipeline, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("parse pipeline spec: %w", err)
+	}
+
+	if len(root.Content) == 0 {
+		return nil, errors.New("pipeline spec is empty")
+	}
+
+	var errs SpecErrors
+	validateSpecNode(root.Content[0], &errs)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	var spec pipelineSpec
+	if err := root.Decode(&spec); err != nil {
+		return nil, fmt.Errorf("decode pipeline spec: %w", err)
+	}
+
+	variables := make(map[string]string, len(spec.Variables)+len(vars))
+	for k, v := range spec.Variables {
+		variables[k] = v
+	}
+	for k, v := range vars {
+		variables[k] = v
+	}
+
+	expand := func(line int, s string) string {
+		expanded, err := expandVariables(s, variables)
+		if err != nil {
+			errs = append(errs, SpecError{Line: line, Message: err.Error()})
+		}
+		return expanded
+	}
+
+	builder := NewPipelineBuilder(expand(specKeyLine(root.Content[0], "name"), spec.Name)).
+		DeleteOnCompleted(spec.DeleteOnCompleted)
+
+	lines := make(map[string]int, len(spec.Errands))
+	names := make([]string, len(spec.Errands))
+	for i, errandSpec := range spec.Errands {
+		name := expand(errandSpec.line, errandSpec.Name)
+		names[i] = name
+
+		switch {
+		case name == "":
+			errs = append(errs, SpecError{Line: errandSpec.line, Message: fmt.Sprintf("errand %d has no name", i)})
+		case lines[name] != 0:
+			errs = append(errs, SpecError{Line: errandSpec.line, Message: fmt.Sprintf("duplicate errand name %q, first defined on line %d", name, lines[name])})
+		default:
+			lines[name] = errandSpec.line
+		}
+
+		if errandSpec.Type == "" {
+			errs = append(errs, SpecError{Line: errandSpec.line, Message: fmt.Sprintf("errand %q has no type", name)})
+		}
+
+		errand := &schemas.Errand{
+			Name: name,
+			Type: errandSpec.Type,
+			Data: expandData(errandSpec.Data, func(s string) string { return expand(errandSpec.line, s) }),
+		}
+		errand.Options.TTL = errandSpec.Options.TTL
+		errand.Options.Retries = errandSpec.Options.Retries
+		errand.Options.Priority = errandSpec.Options.Priority
+		errand.Options.DeleteOnCompleted = errandSpec.Options.DeleteOnCompleted
+
+		builder.AddErrand(errand)
+	}
+
+	for i, errandSpec := range spec.Errands {
+		name := names[i]
+		for _, dependency := range errandSpec.DependsOn {
+			dependency = expand(errandSpec.line, dependency)
+			if lines[dependency] == 0 {
+				errs = append(errs, SpecError{Line: errandSpec.line, Message: fmt.Sprintf("errand %q depends on unknown errand %q", name, dependency)})
+				continue
+			}
+
+			builder.DependsOn(name, dependency)
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	pipeline, err := builder.Build()
+	if err != nil {
+		var cycle *CycleError
+		if errors.As(err, &cycle) {
+			return nil, SpecErrors{{Line: lines[cycle.Path[0]], Message: cycle.Error()}}
+		}
+
+		return nil, SpecErrors{{Message: err.Error()}}
+	}
+
+	return pipeline, nil
+}
+
+// validateSpecNode checks the structure of a pipeline spec, recording errors for unknown keys and values of the wrong kind.
+func validateSpecNode(node *yaml.Node, errs *SpecErrors) {
+	if node.Kind != yaml.MappingNode {
+		*errs = append(*errs, SpecError{Line: node.Line, Message: "pipeline spec must be a mapping"})
+		return
+	}
+
+	checkSpecKeys(node, pipelineSpecKeys, "pipeline", errs)
+	if specValue(node, "name") == nil {
+		*errs = append(*errs, SpecError{Line: node.Line, Message: "pipeline has no name"})
+	}
+
+	if variables := specValue(node, "variables"); variables != nil && variables.Kind != yaml.MappingNode {
+		*errs = append(*errs, SpecError{Line: variables.Line, Message: "variables must be a mapping"})
+	}
+
+	errands := specValue(node, "errands")
+	if errands == nil || errands.Kind != yaml.SequenceNode || len(errands.Content) == 0 {
+		line := node.Line
+		if errands != nil {
+			line = errands.Line
+		}
+		*errs = append(*errs, SpecError{Line: line, Message: "errands must be a non-empty list"})
+		return
+	}
+
+	for _, errand := range errands.Content {
+		if errand.Kind != yaml.MappingNode {
+			*errs = append(*errs, SpecError{Line: errand.Line, Message: "errand must be a mapping"})
+			continue
+		}
+
+		checkSpecKeys(errand, errandSpecKeys, "errand", errs)
+
+		if data := specValue(errand, "data"); data != nil && data.Kind != yaml.MappingNode {
+			*errs = append(*errs, SpecError{Line: data.Line, Message: "data must be a mapping"})
+		}
+
+		if options := specValue(errand, "options"); options != nil {
+			if options.Kind != yaml.MappingNode {
+				*errs = append(*errs, SpecError{Line: options.Line, Message: "options must be a mapping"})
+			} else {
+				checkSpecKeys(options, optionsSpecKeys, "option", errs)
+			}
+		}
+
+		if dependsOn := specValue(errand, "dependsOn"); dependsOn != nil && dependsOn.Kind != yaml.SequenceNode {
+			*errs = append(*errs, SpecError{Line: dependsOn.Line, Message: "dependsOn must be a list of errand names"})
+		}
+	}
+}
+
+func checkSpecKeys(node *yaml.Node, allowed []string, kind string, errs *SpecErrors) {
+	for i := 0; i < len(node.Content); i += 2 {
+		key := node.Content[i]
+		if !containsString(allowed, key.Value) {
+			*errs = append(*errs, SpecError{
+				Line:    key.Line,
+				Message: fmt.Sprintf("unknown %s field %q; expected one of %s", kind, key.Value, strings.Join(allowed, ", ")),
+			})
+		}
+	}
+}
+
+// specValue returns the value for key in a mapping node, or nil if it isn't set.
+func specValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+func specKeyLine(node *yaml.Node, key string) int {
+	if value := specValue(node, key); value != nil {
+		return value.Line
+	}
+
+	return node.Line
+}
+
+// variablePattern matches ${name} variable references.
+var variablePattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// expandVariables replaces ${name} references in s with their values, returning an error listing any that aren't defined.
+func expandVariables(s string, vars map[string]string) (string, error) {
+	var missing []string
+	expanded := variablePattern.ReplaceAllStringFunc(s, func(ref string) string {
+		name := variablePattern.FindStringSubmatch(ref)[1]
+		value, exists := vars[name]
+		if !exists {
+			missing = append(missing, name)
+			return ref
+		}
+		return value
+	})
+
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return s, fmt.Errorf("undefined variable(s) in %q: %s", s, strings.Join(missing, ", "))
+	}
+
+	return expanded, nil
+}
+
+// expandData applies expand to every string value in data, including ones nested in maps and lists.
+func expandData(data map[string]interface{}, expand func(string) string) map[string]interface{} {
+	if data == nil {
+		return nil
+	}
+
+	expanded := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		expanded[k] = expandValue(v, expand)
+	}
+
+	return expanded
+}
+
+func expandValue(value interface{}, expand func(string) string) interface{} {
+	switch v := value.(type) {
+	case string:
+		return expand(v)
+	case map[string]interface{}:
+		return expandData(v, expand)
+	case []interface{}:
+		expanded := make([]interface{}, len(v))
+		for i, item := range v {
+			expanded[i] = expandValue(item, expand)
+		}
+		return expanded
+	default:
+		return value
+	}
+}
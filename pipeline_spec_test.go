@@ -0,0 +1,107 @@
+package errands
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePipelineSpec(t *testing.T) {
+	spec := `
+name: etl-${ticker}-${date}
+deleteOnCompleted: true
+variables:
+  date: "2022-01-01"
+errands:
+  - name: extract-${ticker}
+    type: extract
+    data:
+      ticker: ${ticker}
+      price: "$5"
+      paths: ["/in/${date}", 3]
+    options: {ttl: 600, retries: 2}
+  - name: load
+    type: load
+    dependsOn: ["extract-${ticker}"]
+`
+	pipeline, err := ParsePipelineSpec([]byte(spec), map[string]string{"ticker": "AAPL"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pipeline.Name != "etl-AAPL-2022-01-01" || !pipeline.DeleteOnCompleted {
+		t.Errorf("unexpected pipeline: %+v", pipeline)
+	}
+
+	extract := pipeline.Errands[0]
+	if extract.Name != "extract-AAPL" || extract.Options.TTL != 600 || extract.Options.Retries != 2 {
+		t.Errorf("unexpected errand: %+v", extract)
+	}
+
+	expectedData := map[string]interface{}{
+		"ticker": "AAPL",
+		"price":  "$5",
+		"paths":  []interface{}{"/in/2022-01-01", 3},
+	}
+	if !reflect.DeepEqual(extract.Data, expectedData) {
+		t.Errorf("unexpected data: %#v", extract.Data)
+	}
+
+	if len(pipeline.Dependencies) != 1 || pipeline.Dependencies[0].Target != "load" || pipeline.Dependencies[0].DependsOn != "extract-AAPL" {
+		t.Errorf("unexpected dependencies: %+v", pipeline.Dependencies)
+	}
+}
+
+func TestParsePipelineSpecErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+		err  string
+	}{
+		{
+			name: "structure",
+			spec: `{
+  "name": "p",
+  "errands": [
+    {"name": "a", "type": "t", "timeout": 5},
+    {"name": "b", "type": "t", "options": {"retry": 1}}
+  ]
+}`,
+			err: "line 4: unknown errand field \"timeout\"; expected one of name, type, data, options, dependsOn\n" +
+				"line 5: unknown option field \"retry\"; expected one of ttl, retries, priority, deleteOnCompleted",
+		},
+		{
+			name: "references",
+			spec: `name: p
+errands:
+  - name: a
+    type: t
+  - name: a
+    dependsOn: [c]
+  - name: ${missing}
+    type: t
+`,
+			err: "line 5: duplicate errand name \"a\", first defined on line 3\n" +
+				"line 5: errand \"a\" has no type\n" +
+				"line 7: undefined variable(s) in \"${missing}\": missing\n" +
+				"line 5: errand \"a\" depends on unknown errand \"c\"",
+		},
+		{
+			name: "cycle",
+			spec: `name: p
+errands:
+  - {name: a, type: t, dependsOn: [b]}
+  - {name: b, type: t, dependsOn: [a]}
+`,
+			err: "line 3: dependency cycle: a -> b -> a",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParsePipelineSpec([]byte(test.spec), nil)
+			if err == nil || err.Error() != test.err {
+				t.Errorf("expected error:\n%s\ngot:\n%v", test.err, err)
+			}
+		})
+	}
+}
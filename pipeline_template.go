@@ -0,0 +1,194 @@
+package errands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/polygon-io/errands-server/schemas"
+)
+
+// PipelineTemplate describes the shape of a pipeline whose errand names and data contain ${param} references.
+// Instantiate substitutes the parameters and builds a pipeline with a PipelineBuilder.
+// Errands are referred to by their unsubstituted names in DependsOn.
+type PipelineTemplate struct {
+	name              string
+	deleteOnCompleted bool
+
+	nodes        []*templateNode
+	dependencies map[string][]string
+	targets      []string
+}
+
+type templateNode struct {
+	errand *schemas.Errand
+
+	// fanOutParam is set for fan-out nodes, which are expanded into one errand per value of the parameter.
+	fanOutParam string
+	join        *schemas.Errand
+}
+
+// NewPipelineTemplate creates a template for pipelines named name, which may contain ${param} references.
+func NewPipelineTemplate(name string) *PipelineTemplate {
+	return &PipelineTemplate{
+		name:         name,
+		dependencies: make(map[string][]string),
+	}
+}
+
+// DeleteOnCompleted sets whether the server should delete pipelines created from this template once they complete.
+func (t *PipelineTemplate) DeleteOnCompleted(deleteOnCompleted bool) *PipelineTemplate {
+	t.deleteOnCompleted = deleteOnCompleted
+	return t
+}
+
+// AddErrand adds an errand to the template. Its name and string data values may contain ${param} references.
+func (t *PipelineTemplate) AddErrand(errand *schemas.Errand) *PipelineTemplate {
+	t.nodes = append(t.nodes, &templateNode{errand: errand})
+	return t
+}
+
+// FanOut adds an errand that's expanded into one parallel errand for each value of the fan-out parameter param,
+// plus a join errand that depends on all of them. The errand's name must reference ${param} so the expanded errands
+// have unique names. Dependencies of the fan-out apply to each expanded errand, and errands that depend on the
+// fan-out (by its unsubstituted name) or on the join wait for the join.
+func (t *PipelineTemplate) FanOut(param string, errand *schemas.Errand, join *schemas.Errand) *PipelineTemplate {
+	t.nodes = append(t.nodes, &templateNode{errand: errand, fanOutParam: param, join: join})
+	return t
+}
+
+// DependsOn makes the errand named target wait for each of the errands named in dependencies to complete.
+func (t *PipelineTemplate) DependsOn(target string, dependencies ...string) *PipelineTemplate {
+	if _, exists := t.dependencies[target]; !exists {
+		t.targets = append(t.targets, target)
+	}
+
+	t.dependencies[target] = append(t.dependencies[target], dependencies...)
+	return t
+}
+
+// Instantiate substitutes params into the template and builds the resulting pipeline.
+// fanOut gives the values each fan-out parameter is expanded over.
+func (t *PipelineTemplate) Instantiate(params map[string]string, fanOut map[string][]string) (*schemas.Pipeline, error) {
+	name, err := expandVariables(t.name, params)
+	if err != nil {
+		return nil, fmt.Errorf("pipeline name: %w", err)
+	}
+
+	builder := NewPipelineBuilder(name).DeleteOnCompleted(t.deleteOnCompleted)
+
+	// asTarget maps a node's template name to the errands that take on its dependencies,
+	// and asDependency to the errand that others wait on when they depend on it.
+	asTarget := make(map[string][]string)
+	asDependency := make(map[string]string)
+
+	for _, node := range t.nodes {
+		templateName := node.errand.Name
+
+		if node.fanOutParam == "" {
+			errand, err := instantiateErrand(node.errand, params)
+			if err != nil {
+				return nil, err
+			}
+
+			builder.AddErrand(errand)
+			asTarget[templateName] = []string{errand.Name}
+			asDependency[templateName] = errand.Name
+			continue
+		}
+
+		if !strings.Contains(templateName, "${"+node.fanOutParam+"}") {
+			return nil, fmt.Errorf("fan-out errand %s must reference ${%s} in its name", templateName, node.fanOutParam)
+		}
+
+		values := fanOut[node.fanOutParam]
+		if len(values) == 0 {
+			return nil, fmt.Errorf("no values given for fan-out parameter %s", node.fanOutParam)
+		}
+
+		if node.join == nil {
+			return nil, fmt.Errorf("fan-out errand %s has no join errand", templateName)
+		}
+
+		join, err := instantiateErrand(node.join, params)
+		if err != nil {
+			return nil, err
+		}
+
+		var expanded []string
+		for _, value := range values {
+			errand, err := instantiateErrand(node.errand, withParam(params, node.fanOutParam, value))
+			if err != nil {
+				return nil, err
+			}
+
+			builder.AddErrand(errand)
+			expanded = append(expanded, errand.Name)
+		}
+
+		builder.AddErrand(join).DependsOn(join.Name, expanded...)
+
+		asTarget[templateName] = expanded
+		asDependency[templateName] = join.Name
+		asTarget[node.join.Name] = []string{join.Name}
+		asDependency[node.join.Name] = join.Name
+	}
+
+	for _, target := range t.targets {
+		targetNames, exists := asTarget[target]
+		if !exists {
+			return nil, fmt.Errorf("dependency references unknown errand: %s", target)
+		}
+
+		for _, dependency := range t.dependencies[target] {
+			dependencyName, exists := asDependency[dependency]
+			if !exists {
+				return nil, fmt.Errorf("errand %s depends on unknown errand: %s", target, dependency)
+			}
+
+			for _, targetName := range targetNames {
+				builder.DependsOn(targetName, dependencyName)
+			}
+		}
+	}
+
+	return builder.Build()
+}
+
+// instantiateErrand returns a copy of errand with params substituted into its name and string data values.
+func instantiateErrand(errand *schemas.Errand, params map[string]string) (*schemas.Errand, error) {
+	name, err := expandVariables(errand.Name, params)
+	if err != nil {
+		return nil, fmt.Errorf("errand %s: %w", errand.Name, err)
+	}
+
+	var dataErr error
+	data := expandData(errand.Data, func(s string) string {
+		expanded, err := expandVariables(s, params)
+		if err != nil && dataErr == nil {
+			dataErr = fmt.Errorf("errand %s data: %w", errand.Name, err)
+		}
+		return expanded
+	})
+	if dataErr != nil {
+		return nil, dataErr
+	}
+
+	instance := &schemas.Errand{
+		Name: name,
+		Type: errand.Type,
+		Data: data,
+	}
+	instance.Options = errand.Options
+
+	return instance, nil
+}
+
+func withParam(params map[string]string, key, value string) map[string]string {
+	with := make(map[string]string, len(params)+1)
+	for k, v := range params {
+		with[k] = v
+	}
+	with[key] = value
+
+	return with
+}
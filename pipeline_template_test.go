@@ -0,0 +1,71 @@
+package errands
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/polygon-io/errands-server/schemas"
+)
+
+func TestPipelineTemplate(t *testing.T) {
+	template := NewPipelineTemplate("etl-${ticker}-${date}").
+		AddErrand(&schemas.Errand{Name: "extract", Type: "extract", Data: map[string]interface{}{"ticker": "${ticker}"}}).
+		FanOut("partition",
+			&schemas.Errand{Name: "transform-${partition}", Type: "transform", Data: map[string]interface{}{"partition": "${partition}", "date": "${date}"}},
+			&schemas.Errand{Name: "transform-done", Type: "join"}).
+		AddErrand(&schemas.Errand{Name: "load", Type: "load"}).
+		DependsOn("transform-${partition}", "extract").
+		DependsOn("load", "transform-${partition}")
+
+	pipeline, err := template.Instantiate(
+		map[string]string{"ticker": "AAPL", "date": "2022-06-01"},
+		map[string][]string{"partition": {"0", "1"}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pipeline.Name != "etl-AAPL-2022-06-01" {
+		t.Errorf("unexpected pipeline name: %s", pipeline.Name)
+	}
+
+	var names []string
+	for _, errand := range pipeline.Errands {
+		names = append(names, errand.Name)
+	}
+	if expected := []string{"extract", "transform-0", "transform-1", "transform-done", "load"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected errands %v, got %v", expected, names)
+	}
+
+	if data := pipeline.Errands[2].Data; data["partition"] != "1" || data["date"] != "2022-06-01" {
+		t.Errorf("unexpected fan-out data: %v", data)
+	}
+
+	expectedDeps := []*schemas.PipelineDependency{
+		{Target: "transform-0", DependsOn: "extract"},
+		{Target: "transform-1", DependsOn: "extract"},
+		{Target: "transform-done", DependsOn: "transform-0"},
+		{Target: "transform-done", DependsOn: "transform-1"},
+		{Target: "load", DependsOn: "transform-done"},
+	}
+	if !reflect.DeepEqual(pipeline.Dependencies, expectedDeps) {
+		for _, dep := range pipeline.Dependencies {
+			t.Logf("%+v", dep)
+		}
+		t.Error("unexpected dependencies")
+	}
+}
+
+func TestPipelineTemplateErrors(t *testing.T) {
+	template := NewPipelineTemplate("p").
+		FanOut("partition", &schemas.Errand{Name: "transform-${partition}", Type: "t"}, &schemas.Errand{Name: "done", Type: "t"})
+
+	if _, err := template.Instantiate(nil, nil); err == nil || err.Error() != "no values given for fan-out parameter partition" {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	template = NewPipelineTemplate("p-${date}").AddErrand(&schemas.Errand{Name: "a", Type: "t"})
+	if _, err := template.Instantiate(nil, nil); err == nil || err.Error() != `pipeline name: undefined variable(s) in "p-${date}": date` {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -31,7 +31,7 @@ func (e *ErrandsAPI) CreatePipeline(ctx context.Context, pipeline *schemas.Pipel
 
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	response := &CreatePipelineResponse{}
-	if err := requestAndUnmarshalResponse(req, response); err != nil {
+	if err := e.requestAndUnmarshalResponse(EndpointCreate, req, response); err != nil {
 		return nil, err
 	}
 
@@ -50,7 +50,7 @@ func (e *ErrandsAPI) DeletePipeline(ctx context.Context, pipelineID string) (*De
 	}
 
 	response := &DeletePipelineResponse{}
-	if err := requestAndUnmarshalResponse(req, response); err != nil {
+	if err := e.requestAndUnmarshalResponse(EndpointOther, req, response); err != nil {
 		return nil, err
 	}
 
@@ -70,7 +70,7 @@ func (e *ErrandsAPI) GetPipeline(ctx context.Context, pipelineID string) (*GetPi
 	}
 
 	response := &GetPipelineResponse{}
-	if err := requestAndUnmarshalResponse(req, response); err != nil {
+	if err := e.requestAndUnmarshalResponse(EndpointList, req, response); err != nil {
 		return nil, err
 	}
 
@@ -86,6 +86,7 @@ type ListPipelineResponse struct {
 // ListPipelines lists all pipelines filtered by status. If statusFilter is empty string, it will list all pipelines of all statuses.
 // Note that the pipelines in the response will _not_ have their list of `errands` or `dependencies` populated.
 // To see that information you'll have to do a Get for the individual pipeline by ID.
+// IteratePipelines can do that for you, along with filtering by name and creation time and paging.
 func (e *ErrandsAPI) ListPipelines(ctx context.Context, statusFilter string) (*ListPipelineResponse, error) {
 	var query string
 
@@ -102,15 +103,85 @@ func (e *ErrandsAPI) ListPipelines(ctx context.Context, statusFilter string) (*L
 	}
 
 	response := &ListPipelineResponse{}
-	if err := requestAndUnmarshalResponse(req, response); err != nil {
+	if err := e.requestAndUnmarshalResponse(EndpointList, req, response); err != nil {
 		return nil, err
 	}
 
 	return response, nil
 }
 
-func requestAndUnmarshalResponse(req *http.Request, unmarshaller json.Unmarshaler) error {
-	resp, err := http.DefaultClient.Do(req)
+// RetryPipelineResult reports what RetryPipeline did.
+type RetryPipelineResult struct {
+	// Retried are the failed errands that were put back into the inactive state.
+	Retried []*schemas.Errand
+
+	// Blocked are the errands waiting on the retried errands. The server starts them once their dependencies complete.
+	Blocked []*schemas.Errand
+}
+
+// RetryPipeline retries every failed errand in the pipeline, leaving completed errands alone.
+// If retrying an errand fails, the errands retried so far are returned along with the error.
+func (e *ErrandsAPI) RetryPipeline(ctx context.Context, pipelineID string) (*RetryPipelineResult, error) {
+	res, err := e.GetPipeline(ctx, pipelineID)
+	if err != nil {
+		return nil, fmt.Errorf("get pipeline: %w", err)
+	}
+
+	if res.Status != "OK" {
+		return nil, fmt.Errorf("get pipeline %s: response status %q", pipelineID, res.Status)
+	}
+
+	result := &RetryPipelineResult{}
+	retried := make(map[string]bool)
+	for _, errand := range res.Results.Errands {
+		if errand.Status != schemas.StatusFailed {
+			continue
+		}
+
+		retryRes, err := e.RetryErrand(ctx, errand.ID)
+		if err != nil {
+			return result, err
+		}
+
+		result.Retried = append(result.Retried, &retryRes.Results)
+		retried[errand.Name] = true
+	}
+
+	result.Blocked = blockedDependents(&res.Results, retried)
+
+	return result, nil
+}
+
+// blockedDependents returns the blocked errands in the pipeline that depend, directly or transitively, on the named errands.
+func blockedDependents(pipeline *schemas.Pipeline, names map[string]bool) []*schemas.Errand {
+	waiting := make(map[string]bool, len(names))
+	for name := range names {
+		waiting[name] = true
+	}
+
+	// Keep propagating until no new dependents are found; pipelines are small, so this is cheap enough.
+	for changed := true; changed; {
+		changed = false
+		for _, dep := range pipeline.Dependencies {
+			if waiting[dep.DependsOn] && !waiting[dep.Target] {
+				waiting[dep.Target] = true
+				changed = true
+			}
+		}
+	}
+
+	var blocked []*schemas.Errand
+	for _, errand := range pipeline.Errands {
+		if waiting[errand.Name] && !names[errand.Name] && errand.Status == schemas.StatusBlocked {
+			blocked = append(blocked, errand)
+		}
+	}
+
+	return blocked
+}
+
+func (e *ErrandsAPI) requestAndUnmarshalResponse(class EndpointClass, req *http.Request, unmarshaller json.Unmarshaler) error {
+	resp, err := e.do(class, req)
 	if err != nil {
 		return fmt.Errorf("http request: %w", err)
 	}
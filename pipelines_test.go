@@ -0,0 +1,49 @@
+package errands
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRetryPipeline(t *testing.T) {
+	var retried []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/pipeline/p1":
+			w.Write([]byte(`{"status":"OK","results":{"id":"p1","name":"etl","status":"failed",
+				"errands":[
+					{"id":"e1","name":"extract","status":"completed"},
+					{"id":"e2","name":"transform","status":"failed"},
+					{"id":"e3","name":"load","status":"blocked"},
+					{"id":"e4","name":"report","status":"blocked"},
+					{"id":"e5","name":"audit","status":"blocked"}],
+				"dependencies":[
+					{"target":"transform","dependsOn":"extract"},
+					{"target":"load","dependsOn":"transform"},
+					{"target":"report","dependsOn":"load"},
+					{"target":"audit","dependsOn":"other"}]}}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/errand/e2/retry":
+			retried = append(retried, "e2")
+			w.Write([]byte(`{"status":"OK","results":{"id":"e2","name":"transform","status":"inactive"}}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	result, err := New(server.URL).RetryPipeline(context.Background(), "p1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(retried) != 1 || len(result.Retried) != 1 || result.Retried[0].Status != "inactive" {
+		t.Errorf("unexpected retries: %v %+v", retried, result.Retried)
+	}
+
+	if len(result.Blocked) != 2 || result.Blocked[0].Name != "load" || result.Blocked[1].Name != "report" {
+		t.Errorf("unexpected blocked errands: %+v", result.Blocked)
+	}
+}
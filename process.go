@@ -1,7 +1,10 @@
 package errands
 
 import (
+	"context"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	time "time"
 
 	schemas "github.com/polygon-io/errands-server/schemas"
@@ -17,12 +20,43 @@ type Processor struct {
 	ErrandQueue chan *schemas.Errand
 	Fn          func(*schemas.Errand) (map[string]interface{}, error)
 	Procs       []*ProcThread
+
+	// ContextFn is used instead of Fn when set. Its context is cancelled if the errand is lost, see WithHeartbeat.
+	ContextFn func(context.Context, *schemas.Errand) (map[string]interface{}, error)
+
+	// HeartbeatInterval is how often in-flight errands are reported as alive to the server. Zero disables heartbeats.
+	HeartbeatInterval time.Duration
+
+	inFlight int32
+	health   processorHealth
+}
+
+// processorHealth tracks how the processor's polling of the errands server is going.
+type processorHealth struct {
+	mu                 sync.Mutex
+	running            bool
+	lastSuccessfulPoll time.Time
+	consecutiveErrors  int
+	failingSince       time.Time
+}
+
+// ProcessorOption configures optional behaviour of a Processor.
+type ProcessorOption func(*Processor)
+
+// WithHeartbeat makes the processor send a heartbeat for every in-flight errand each interval, so the server
+// knows it's still being worked on. If the server rejects a heartbeat because the errand was deleted or is no
+// longer active, the handler's context is cancelled and its outcome is not reported.
+func WithHeartbeat(interval time.Duration) ProcessorOption {
+	return func(p *Processor) {
+		p.HeartbeatInterval = interval
+	}
 }
 
 // NewProcessor creates and returns a *Processor with the params sent.
 func (e *ErrandsAPI) NewProcessor(
 	topic string, concurrency int,
-	fn func(*schemas.Errand) (map[string]interface{}, error)) (*Processor, error) {
+	fn func(*schemas.Errand) (map[string]interface{}, error),
+	opts ...ProcessorOption) (*Processor, error) {
 	// Create the processor:
 	obj := &Processor{
 		Parent:      e,
@@ -33,6 +67,9 @@ func (e *ErrandsAPI) NewProcessor(
 		Quit:        make(chan (int)),
 		ErrandQueue: make(chan (*schemas.Errand)),
 	}
+	for _, opt := range opts {
+		opt(obj)
+	}
 	// Add it to this APIs Processor list:
 	e.Processors = append(e.Processors, obj)
 	// Actually run the processor:
@@ -41,6 +78,17 @@ func (e *ErrandsAPI) NewProcessor(
 
 }
 
+// NewContextProcessor is like NewProcessor, but fn receives a context that's cancelled when the processor
+// loses the errand. The context is an errands *Context, so fn can also use its logger.
+func (e *ErrandsAPI) NewContextProcessor(
+	topic string, concurrency int,
+	fn func(context.Context, *schemas.Errand) (map[string]interface{}, error),
+	opts ...ProcessorOption) (*Processor, error) {
+	return e.NewProcessor(topic, concurrency, nil, append([]ProcessorOption{func(p *Processor) {
+		p.ContextFn = fn
+	}}, opts...)...)
+}
+
 // Pause pauses the processor. This will not pause the current threads, it will
 // simply stop the processor from processing subsequent items.
 func (p *Processor) Pause() {
@@ -54,6 +102,7 @@ func (p *Processor) Resume() {
 
 func (p *Processor) requestErrandToProcess() {
 	errandRes, err := p.Parent.RequestErrandToProcess(p.Topic)
+	p.recordPoll(err)
 	if err != nil {
 		fmt.Println("Error requesting errand to process:", err)
 		return
@@ -66,6 +115,8 @@ func (p *Processor) requestErrandToProcess() {
 // Run creates the threads, and starts the loop to query for jobs to run.
 func (p *Processor) Run() {
 	ticker := time.NewTicker(4 * time.Second)
+	p.setRunning(true)
+	defer p.setRunning(false)
 	// Create the actually processor threads:
 	for i := 1; i <= p.Concurrency; i++ {
 		obj := p.NewProcThread()
@@ -77,7 +128,8 @@ func (p *Processor) Run() {
 		select {
 		case <-ticker.C:
 			// We have room for another item:
-			if p.procsAwaitingErrands() && len(p.ErrandQueue) == 0 && !p.Paused {
+			// Don't bother polling while the circuit breaker says the server is down:
+			if p.procsAwaitingErrands() && len(p.ErrandQueue) == 0 && !p.Paused && !p.Parent.CircuitOpen() {
 				p.requestErrandToProcess()
 			}
 		case <-p.Quit:
@@ -118,17 +170,75 @@ func (proc *ProcThread) RunThread() {
 		select {
 		case job := <-proc.Processor.ErrandQueue:
 			proc.AwaitingErrand = false
+			atomic.AddInt32(&proc.Processor.inFlight, 1)
 			fmt.Println("Start processing:", job.ID)
 			// Actually Processing the job:
-			res, err := proc.Processor.Fn(job)
-			if err != nil {
+			res, lost, err := proc.Processor.process(job)
+			if lost {
+				fmt.Println("Lost errand, not reporting outcome:", job.ID)
+			} else if err != nil {
 				fmt.Println("Error processing:", job.ID, "Err:", err)
-				proc.Processor.Parent.FailErrand(job.ID, err.Error())
+				proc.Processor.Parent.reportOutcome(&OutboxEntry{ErrandID: job.ID, Failed: true, Reason: err.Error()})
 			} else {
 				fmt.Println("Completed processing:", job.ID)
-				proc.Processor.Parent.CompleteErrand(job.ID, res)
+				proc.Processor.Parent.reportOutcome(&OutboxEntry{ErrandID: job.ID, Results: res})
 			}
+			atomic.AddInt32(&proc.Processor.inFlight, -1)
 			proc.AwaitingErrand = true
 		}
 	}
 }
+
+// process runs the handler on job while sending heartbeats for it, if enabled.
+// lost is true if the server rejected a heartbeat, meaning the errand no longer belongs to this processor.
+func (p *Processor) process(job *schemas.Errand) (res map[string]interface{}, lost bool, err error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var lostFlag int32
+	if p.HeartbeatInterval > 0 {
+		done := make(chan struct{})
+		defer func() { <-done }()
+
+		go func() {
+			defer close(done)
+			if p.heartbeat(ctx, job.ID) {
+				atomic.StoreInt32(&lostFlag, 1)
+				cancel()
+			}
+		}()
+	}
+
+	if p.ContextFn != nil {
+		res, err = p.ContextFn(NewContext(ctx, job.ID), job)
+	} else {
+		res, err = p.Fn(job)
+	}
+
+	cancel()
+	return res, atomic.LoadInt32(&lostFlag) == 1, err
+}
+
+// heartbeat updates the errand every HeartbeatInterval until ctx is done.
+// It returns true if the server rejected a heartbeat. Network errors are logged and retried on the next tick.
+func (p *Processor) heartbeat(ctx context.Context, errandID string) bool {
+	ticker := time.NewTicker(p.HeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+			res, err := p.Parent.UpdateErrandContext(ctx, errandID, 0)
+			if err != nil {
+				fmt.Println("Error sending heartbeat:", errandID, "Err:", err)
+				continue
+			}
+			if res.Status != "OK" {
+				fmt.Println("Heartbeat rejected:", errandID, "Status:", res.Status)
+				return true
+			}
+		}
+	}
+}
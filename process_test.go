@@ -0,0 +1,54 @@
+package errands
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	schemas "github.com/polygon-io/errands-server/schemas"
+)
+
+func TestHeartbeatCancelsLostErrand(t *testing.T) {
+	var heartbeats int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/v1/errand/abc" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+
+		if atomic.AddInt32(&heartbeats, 1) < 3 {
+			w.Write([]byte(`{"status":"OK","results":{"id":"abc","status":"active"}}`))
+			return
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"message":"Internal Server Error!","error":"errand must be in active state to update progress"}`))
+	}))
+	defer server.Close()
+
+	processor := &Processor{
+		Parent:            New(server.URL),
+		HeartbeatInterval: 10 * time.Millisecond,
+		ContextFn: func(ctx context.Context, errand *schemas.Errand) (map[string]interface{}, error) {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(5 * time.Second):
+				return nil, nil
+			}
+		},
+	}
+
+	_, lost, err := processor.process(&schemas.Errand{ID: "abc"})
+	if !lost {
+		t.Error("expected errand to be lost")
+	}
+	if err != context.Canceled {
+		t.Errorf("expected handler to see context.Canceled, got %v", err)
+	}
+	if n := atomic.LoadInt32(&heartbeats); n != 3 {
+		t.Errorf("expected 3 heartbeats, got %d", n)
+	}
+}
@@ -0,0 +1,103 @@
+package errands
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// EndpointClass groups errands server endpoints so they can be rate limited separately.
+type EndpointClass string
+
+const (
+	// EndpointCreate covers creating errands and pipelines.
+	EndpointCreate EndpointClass = "create"
+	// EndpointClaim covers requesting an errand to process.
+	EndpointClaim EndpointClass = "claim"
+	// EndpointReport covers completing, failing and updating errands.
+	EndpointReport EndpointClass = "report"
+	// EndpointList covers getting and listing errands and pipelines.
+	EndpointList EndpointClass = "list"
+	// EndpointOther covers everything else, like deletes.
+	EndpointOther EndpointClass = "other"
+)
+
+// RateLimitStats describes how much requests of an endpoint class have been held back by the rate limiter.
+type RateLimitStats struct {
+	Requests int64
+	Waited   int64
+	WaitTime time.Duration
+}
+
+type rateLimiter struct {
+	limiter *rate.Limiter
+	stats   RateLimitStats
+}
+
+type rateLimiters struct {
+	mu       sync.Mutex
+	limiters map[EndpointClass]*rateLimiter
+}
+
+// SetRateLimit limits requests of the given endpoint class to limit per second, allowing bursts of up to burst
+// requests. Requests block until the limiter allows them or their context is done. A limit of rate.Inf removes the limit.
+func (e *ErrandsAPI) SetRateLimit(class EndpointClass, limit rate.Limit, burst int) {
+	e.rateLimits.mu.Lock()
+	defer e.rateLimits.mu.Unlock()
+
+	if e.rateLimits.limiters == nil {
+		e.rateLimits.limiters = make(map[EndpointClass]*rateLimiter)
+	}
+
+	if existing, exists := e.rateLimits.limiters[class]; exists {
+		existing.limiter.SetLimit(limit)
+		existing.limiter.SetBurst(burst)
+		return
+	}
+
+	e.rateLimits.limiters[class] = &rateLimiter{limiter: rate.NewLimiter(limit, burst)}
+}
+
+// RateLimitStats returns the rate limiter stats for every endpoint class that has a rate limit.
+func (e *ErrandsAPI) RateLimitStats() map[EndpointClass]RateLimitStats {
+	e.rateLimits.mu.Lock()
+	defer e.rateLimits.mu.Unlock()
+
+	stats := make(map[EndpointClass]RateLimitStats, len(e.rateLimits.limiters))
+	for class, limiter := range e.rateLimits.limiters {
+		stats[class] = limiter.stats
+	}
+
+	return stats
+}
+
+func (e *ErrandsAPI) waitForRateLimit(ctx context.Context, class EndpointClass) error {
+	e.rateLimits.mu.Lock()
+	limiter, exists := e.rateLimits.limiters[class]
+	e.rateLimits.mu.Unlock()
+
+	if !exists {
+		return nil
+	}
+
+	start := time.Now()
+	err := limiter.limiter.Wait(ctx)
+	waited := time.Since(start)
+
+	e.rateLimits.mu.Lock()
+	limiter.stats.Requests++
+	if waited > time.Millisecond {
+		limiter.stats.Waited++
+	}
+	limiter.stats.WaitTime += waited
+	e.rateLimits.mu.Unlock()
+
+	if err != nil {
+		return fmt.Errorf("wait for %s rate limit: %w", class, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package errands
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/polygon-io/errands-server/schemas"
+	"golang.org/x/time/rate"
+)
+
+func TestRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"OK"}`))
+	}))
+	defer server.Close()
+
+	api := New(server.URL)
+	api.SetRateLimit(EndpointList, rate.Every(50*time.Millisecond), 1)
+
+	for i := 0; i < 3; i++ {
+		if _, err := api.GetErrands(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats := api.RateLimitStats()[EndpointList]
+	if stats.Requests != 3 {
+		t.Errorf("expected 3 requests, got %d", stats.Requests)
+	}
+	if stats.Waited != 2 || stats.WaitTime < 50*time.Millisecond {
+		t.Errorf("expected the last 2 requests to wait, got %+v", stats)
+	}
+
+	// Other classes aren't limited.
+	if _, err := api.DeleteErrand("abc"); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := api.ListPipelines(ctx, ""); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the limiter to give up when the context is done, got %v", err)
+	}
+}
+
+func TestRateLimitContext(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Write([]byte(`{"status":"OK"}`))
+	}))
+	defer server.Close()
+
+	api := New(server.URL)
+	for _, class := range []EndpointClass{EndpointCreate, EndpointClaim, EndpointReport, EndpointList, EndpointOther} {
+		api.SetRateLimit(class, rate.Every(time.Hour), 1)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"GetErrandsContext", func() error { _, err := api.GetErrandsContext(ctx); return err }},
+		{"ListErrandsContext", func() error { _, err := api.ListErrandsContext(ctx, "type", "a"); return err }},
+		{"CreateErrandContext", func() error { _, err := api.CreateErrandContext(ctx, &schemas.Errand{Type: "a"}); return err }},
+		{"RequestErrandToProcessContext", func() error { _, err := api.RequestErrandToProcessContext(ctx, "a"); return err }},
+		{"FailErrandContext", func() error { _, err := api.FailErrandContext(ctx, "abc", "boom"); return err }},
+		{"CompleteErrandContext", func() error { _, err := api.CompleteErrandContext(ctx, "abc", nil); return err }},
+		{"UpdateErrandContext", func() error { _, err := api.UpdateErrandContext(ctx, "abc", 0); return err }},
+		{"DeleteErrandContext", func() error { _, err := api.DeleteErrandContext(ctx, "abc"); return err }},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.call(); !errors.Is(err, context.Canceled) {
+				t.Errorf("expected the limiter to give up when the context is done, got %v", err)
+			}
+		})
+	}
+
+	if n := atomic.LoadInt32(&requests); n != 0 {
+		t.Errorf("expected no requests to be sent, got %d", n)
+	}
+}
@@ -0,0 +1,216 @@
+package errands
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/polygon-io/errands-server/schemas"
+)
+
+// DefaultPollInterval is how often the Wait functions poll the errands server when no interval is given.
+const DefaultPollInterval = 2 * time.Second
+
+// WaitOptions configures WaitForPipeline.
+type WaitOptions struct {
+	// PollInterval is how often to poll the server. Defaults to DefaultPollInterval.
+	PollInterval time.Duration
+
+	// OnErrandStatusChange, if set, is called every time an errand in the pipeline is seen with a new status.
+	// previous is empty the first time an errand is seen.
+	OnErrandStatusChange func(errand *schemas.Errand, previous schemas.Status)
+}
+
+// ErrandSummary describes how an errand in a pipeline ran.
+type ErrandSummary struct {
+	ID     string
+	Name   string
+	Type   string
+	Status schemas.Status
+
+	// Duration is the time between the errand's last start and when it completed or failed.
+	Duration time.Duration
+
+	// FailureReason is the reason the errand last failed, if it did.
+	FailureReason string
+}
+
+// PipelineSummary describes a pipeline that reached a terminal state.
+type PipelineSummary struct {
+	Pipeline *schemas.Pipeline
+	Status   schemas.Status
+	Duration time.Duration
+	Errands  []ErrandSummary
+}
+
+// WaitForPipeline polls the pipeline until it's completed or failed and returns a summary of how it ran.
+// Pipelines created with DeleteOnCompleted are deleted by the server as soon as they complete,
+// so if such a pipeline disappears it's reported as completed based on the last time it was seen.
+func (e *ErrandsAPI) WaitForPipeline(ctx context.Context, pipelineID string, opts WaitOptions) (*PipelineSummary, error) {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DefaultPollInterval
+	}
+
+	ticker := time.NewTicker(opts.PollInterval)
+	defer ticker.Stop()
+
+	var lastSeen *schemas.Pipeline
+	statuses := make(map[string]schemas.Status)
+
+	for {
+		res, err := e.GetPipeline(ctx, pipelineID)
+		if err != nil {
+			return nil, fmt.Errorf("get pipeline: %w", err)
+		}
+
+		if res.Status == "not_found" {
+			if lastSeen == nil || !lastSeen.DeleteOnCompleted {
+				return nil, fmt.Errorf("pipeline %s not found", pipelineID)
+			}
+
+			lastSeen.Status = schemas.StatusCompleted
+			return summarizePipeline(lastSeen), nil
+		}
+
+		pipeline := &res.Results
+		lastSeen = pipeline
+
+		if opts.OnErrandStatusChange != nil {
+			for _, errand := range pipeline.Errands {
+				if previous := statuses[errand.Name]; previous != errand.Status {
+					opts.OnErrandStatusChange(errand, previous)
+				}
+				statuses[errand.Name] = errand.Status
+			}
+		}
+
+		if pipeline.Status == schemas.StatusCompleted || pipeline.Status == schemas.StatusFailed {
+			return summarizePipeline(pipeline), nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func summarizePipeline(pipeline *schemas.Pipeline) *PipelineSummary {
+	summary := &PipelineSummary{
+		Pipeline: pipeline,
+		Status:   pipeline.Status,
+		Errands:  make([]ErrandSummary, 0, len(pipeline.Errands)),
+	}
+
+	if pipeline.StartedMillis > 0 && pipeline.EndedMillis >= pipeline.StartedMillis {
+		summary.Duration = time.Duration(pipeline.EndedMillis-pipeline.StartedMillis) * time.Millisecond
+	}
+
+	for _, errand := range pipeline.Errands {
+		summary.Errands = append(summary.Errands, ErrandSummary{
+			ID:            errand.ID,
+			Name:          errand.Name,
+			Type:          errand.Type,
+			Status:        errand.Status,
+			Duration:      errandDuration(errand),
+			FailureReason: FailureReason(errand),
+		})
+	}
+
+	return summary
+}
+
+// errandDuration returns how long the errand's last run took, or zero if it hasn't finished.
+func errandDuration(errand *schemas.Errand) time.Duration {
+	end := errand.Completed
+	if errand.Status == schemas.StatusFailed {
+		end = errand.Failed
+	}
+
+	if errand.Started == 0 || end < errand.Started {
+		return 0
+	}
+
+	return time.Duration(end-errand.Started) * time.Millisecond
+}
+
+// FailureReason returns the message of the errand's most recent error log, which is where the server
+// records the reason an errand failed. It returns "" if the errand has no error logs.
+func FailureReason(errand *schemas.Errand) string {
+	for i := len(errand.Logs) - 1; i >= 0; i-- {
+		if errand.Logs[i].Severity == "ERROR" {
+			return errand.Logs[i].Message
+		}
+	}
+
+	return ""
+}
+
+// ErrandFailedError is returned by Call when the errand failed.
+type ErrandFailedError struct {
+	Errand *schemas.Errand
+	Reason string
+}
+
+func (e *ErrandFailedError) Error() string {
+	return fmt.Sprintf("errand %s failed: %s", e.Errand.ID, e.Reason)
+}
+
+// WaitForErrand polls the errand until it's completed or failed and returns it. A failed errand is returned
+// without an error; use its status and logs, or Call, to find out why it failed.
+// Errands created with DeleteOnCompleted can't be waited for since the server deletes them when they complete.
+// Each poll lists every errand on the server; see GetErrand.
+func (e *ErrandsAPI) WaitForErrand(ctx context.Context, errandID string) (*schemas.Errand, error) {
+	ticker := time.NewTicker(DefaultPollInterval)
+	defer ticker.Stop()
+
+	for {
+		res, err := e.GetErrand(ctx, errandID)
+		if err != nil {
+			return nil, err
+		}
+
+		errand := &res.Results
+		if errand.Status == schemas.StatusCompleted || errand.Status == schemas.StatusFailed {
+			return errand, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Call creates an errand of type topic with the given data, waits for it to be processed and returns its results.
+// If the errand fails the error is an *ErrandFailedError.
+//
+// errands-server v1.1.0 doesn't keep the results errands are completed with, so against it the results are always
+// empty and Call is only useful for waiting on an errand and finding out whether it failed.
+func (e *ErrandsAPI) Call(ctx context.Context, topic string, data map[string]interface{}) (map[string]interface{}, error) {
+	created, err := e.CreateErrandContext(ctx, &schemas.Errand{
+		Name: topic,
+		Type: topic,
+		Data: data,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create errand: %w", err)
+	}
+
+	if created.Results.ID == "" {
+		return nil, fmt.Errorf("create errand: response status %q", created.Status)
+	}
+
+	errand, err := e.WaitForErrand(ctx, created.Results.ID)
+	if err != nil {
+		return nil, fmt.Errorf("wait for errand: %w", err)
+	}
+
+	if errand.Status == schemas.StatusFailed {
+		return nil, &ErrandFailedError{Errand: errand, Reason: FailureReason(errand)}
+	}
+
+	return errand.Results, nil
+}
@@ -0,0 +1,160 @@
+package errands
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/polygon-io/errands-server/schemas"
+)
+
+func TestWaitForPipeline(t *testing.T) {
+	responses := []string{
+		`{"status":"OK","results":{"id":"p1","name":"etl","status":"active","startedMillis":1000,"errands":[
+			{"id":"e1","name":"extract","type":"extract","status":"active","started":1000},
+			{"id":"e2","name":"load","type":"load","status":"blocked"}]}}`,
+		`{"status":"OK","results":{"id":"p1","name":"etl","status":"failed","startedMillis":1000,"endedMillis":9000,"errands":[
+			{"id":"e1","name":"extract","type":"extract","status":"completed","started":1000,"compelted":4000},
+			{"id":"e2","name":"load","type":"load","status":"failed","started":5000,"failed":9000,
+			 "logs":[{"severity":"INFO","message":"Started"},{"severity":"ERROR","message":"disk full"}]}]}}`,
+	}
+
+	var polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/pipeline/p1" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		n := atomic.AddInt32(&polls, 1)
+		w.Write([]byte(responses[n-1]))
+	}))
+	defer server.Close()
+
+	var changes []string
+	summary, err := New(server.URL).WaitForPipeline(context.Background(), "p1", WaitOptions{
+		PollInterval: time.Millisecond,
+		OnErrandStatusChange: func(errand *schemas.Errand, previous schemas.Status) {
+			changes = append(changes, errand.Name+":"+string(previous)+"->"+string(errand.Status))
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if summary.Status != schemas.StatusFailed || summary.Duration != 8*time.Second {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+
+	expectedChanges := []string{"extract:->active", "load:->blocked", "extract:active->completed", "load:blocked->failed"}
+	if len(changes) != len(expectedChanges) {
+		t.Fatalf("expected changes %v, got %v", expectedChanges, changes)
+	}
+	for i := range changes {
+		if changes[i] != expectedChanges[i] {
+			t.Errorf("expected changes %v, got %v", expectedChanges, changes)
+		}
+	}
+
+	extract, load := summary.Errands[0], summary.Errands[1]
+	if extract.Duration != 3*time.Second || extract.FailureReason != "" {
+		t.Errorf("unexpected extract summary: %+v", extract)
+	}
+	if load.Duration != 4*time.Second || load.FailureReason != "disk full" {
+		t.Errorf("unexpected load summary: %+v", load)
+	}
+}
+
+func TestGetErrand(t *testing.T) {
+	server := httptest.NewServer(&fakeServer{})
+	defer server.Close()
+
+	api := New(server.URL)
+	created, err := api.CreateErrand(&schemas.Errand{Name: "resize", Type: "resize"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := api.GetErrand(context.Background(), created.Results.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Results.ID != created.Results.ID || res.Results.Status != schemas.StatusInactive {
+		t.Errorf("unexpected errand: %+v", res.Results)
+	}
+
+	if _, err := api.GetErrand(context.Background(), "missing"); !errors.Is(err, ErrErrandNotFound) {
+		t.Errorf("expected ErrErrandNotFound, got %v", err)
+	}
+}
+
+func TestCall(t *testing.T) {
+	tests := []struct {
+		name    string
+		process func(api *ErrandsAPI, id string) error
+		check   func(t *testing.T, results map[string]interface{}, err error)
+	}{
+		{
+			name: "completed",
+			process: func(api *ErrandsAPI, id string) error {
+				_, err := api.CompleteErrand(id, map[string]interface{}{"url": "small.png"})
+				return err
+			},
+			check: func(t *testing.T, results map[string]interface{}, err error) {
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				// The server drops the results errands are completed with.
+				if len(results) != 0 {
+					t.Errorf("expected no results, got %v", results)
+				}
+			},
+		},
+		{
+			name: "failed",
+			process: func(api *ErrandsAPI, id string) error {
+				_, err := api.FailErrand(id, "bad image")
+				return err
+			},
+			check: func(t *testing.T, results map[string]interface{}, err error) {
+				var failed *ErrandFailedError
+				if !errors.As(err, &failed) {
+					t.Fatalf("expected an *ErrandFailedError, got %v", err)
+				}
+				if failed.Reason != "bad image" || failed.Errand.ID != "e1" {
+					t.Errorf("unexpected error: %+v", failed)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeServer{}
+			server := httptest.NewServer(fake)
+			defer server.Close()
+
+			api := New(server.URL)
+
+			// Process the errand like a processor would, once Call has created it.
+			go func() {
+				for fake.lastID() == "" {
+					time.Sleep(10 * time.Millisecond)
+				}
+
+				if err := tt.process(api, fake.lastID()); err != nil {
+					t.Error(err)
+				}
+			}()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			results, err := api.Call(ctx, "resize", map[string]interface{}{"url": "x.png"})
+			tt.check(t, results, err)
+		})
+	}
+}